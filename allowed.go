@@ -11,10 +11,29 @@ type Allowed []AllowedModule
 
 // AllowedModule is a single entry in the allowed list.
 type AllowedModule struct {
+	// ID identifies the rule in issues and reports. When omitted it is derived
+	// from Module, see RuleID.
+	ID        string              `yaml:"id,omitempty"`
 	Module    string              `yaml:"module"`
 	MatchType MatchType           `yaml:"match-type"`
 	Version   *semver.Constraints `yaml:"version"`
-	Matcher   Matcher             `yaml:"-"`
+	// Severity is the severity of the issues for versions that do not meet
+	// Version, it defaults to error.
+	Severity Severity `yaml:"severity,omitempty"`
+	// Expires is the last day the rule allows the module, it is not allowed
+	// afterwards.
+	Expires *Date   `yaml:"expires,omitempty"`
+	Matcher Matcher `yaml:"-"`
+}
+
+// RuleID returns the ID of the rule, or an ID derived from the module when
+// none is configured, e.g. "allowed/github.com/foo/bar".
+func (r *AllowedModule) RuleID() string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return deriveRuleID(allowedRuleIDPrefix, r.Module)
 }
 
 // CheckVersion returns true if the module version matches the allowed constraint,
@@ -40,3 +59,28 @@ func (r *AllowedModule) NotAllowedReason(moduleVersion string) string {
 
 	return fmt.Sprintf("version `%s` does not meet the allowed version constraint `%s`.", moduleVersion, r.Version)
 }
+
+// expiredModule returns why a module matched by the rule with the given key
+// is blocked because the rule expired.
+func (r *AllowedModule) expiredModule(key, moduleName, moduleVersion string) blockedModule {
+	blocked := r.blockedModule(key, moduleName, moduleVersion,
+		fmt.Sprintf("the allowed rule for the module expired on %s.", r.Expires))
+	blocked.kind = IssueKindExpired
+
+	return blocked
+}
+
+// blockedModule returns why a module matched by the rule with the given key
+// is blocked because its version does not meet the version constraint.
+func (r *AllowedModule) blockedModule(key, moduleName, moduleVersion, reason string) blockedModule {
+	return blockedModule{
+		reason:    reason,
+		kind:      IssueKindVersionConstraint,
+		module:    moduleName,
+		version:   moduleVersion,
+		rule:      key,
+		ruleID:    r.RuleID(),
+		matchType: r.MatchType.orDefault(),
+		severity:  r.Severity.orDefault(),
+	}
+}
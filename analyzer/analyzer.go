@@ -0,0 +1,224 @@
+// Package analyzer provides gomodguard as a go/analysis Analyzer so it can be
+// composed with other analyzers, e.g. via multichecker or go vet -vettool.
+package analyzer
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+const (
+	name = "gomodguard"
+	doc  = "gomodguard reports imports of packages whose module is blocked by the allow and block lists\n\n" +
+		"The go.mod of the module each package belongs to is used to determine the\n" +
+		"required modules and their versions."
+	url = "https://github.com/ryancurrah/gomodguard"
+)
+
+// Analyzer is a gomodguard analyzer that takes its configuration from flags.
+var Analyzer = NewAnalyzer(nil)
+
+// runner holds the state shared by all passes of an analyzer.
+type runner struct {
+	config *gomodguard.Configuration
+
+	configFile             string
+	policyDir              string
+	allowed                stringList
+	blocked                stringList
+	localReplaceDirectives bool
+
+	mu         sync.Mutex
+	loaded     bool
+	loadErr    error
+	processors map[string]*gomodguard.Processor
+}
+
+// NewAnalyzer returns a gomodguard analyzer. When config is nil the
+// configuration is built from the analyzer flags, otherwise the given
+// configuration is used and no flags are registered.
+func NewAnalyzer(config *gomodguard.Configuration) *analysis.Analyzer {
+	r := &runner{
+		config:     config,
+		processors: make(map[string]*gomodguard.Processor),
+	}
+
+	a := &analysis.Analyzer{
+		Name: name,
+		Doc:  doc,
+		URL:  url,
+		Run:  r.run,
+	}
+
+	if config == nil {
+		a.Flags.StringVar(&r.configFile, "config", "",
+			"Path to a .gomodguard.yaml configuration file")
+		a.Flags.StringVar(&r.policyDir, "policy_dir", "",
+			"Directory to look up extended config files in")
+		a.Flags.Var(&r.allowed, "allowed",
+			"Comma separated list of modules to add to the allowed list")
+		a.Flags.Var(&r.blocked, "blocked",
+			"Comma separated list of modules to add to the blocked list")
+		a.Flags.BoolVar(&r.localReplaceDirectives, "local_replace_directives", false,
+			"Block modules with a local replace directive")
+	} else {
+		r.loaded = true
+	}
+
+	return a
+}
+
+// run reports the blocked imports of every file of the package.
+func (r *runner) run(pass *analysis.Pass) (any, error) {
+	if len(pass.Files) == 0 {
+		return nil, nil //nolint:nilnil // Nothing to analyze.
+	}
+
+	filename := pass.Fset.Position(pass.Files[0].Package).Filename
+
+	processor, err := r.processor(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range pass.Files {
+		tokenFile := pass.Fset.File(file.Pos())
+		if tokenFile == nil {
+			continue
+		}
+
+		for _, issue := range processor.ProcessFile(pass.Fset, file) {
+			pos := tokenFile.Pos(issue.Position.Offset)
+			end := pos
+
+			for _, spec := range file.Imports {
+				if spec.Pos() == pos {
+					end = spec.End()
+					break
+				}
+			}
+
+			message := issue.Reason
+			if !issue.IsError() {
+				// Diagnostics have no severity, so it is part of the message.
+				message = fmt.Sprintf("%s: %s", issue.Severity, issue.Reason)
+			}
+
+			var suggestedFixes []analysis.SuggestedFix
+
+			if edit, ok := gomodguard.RewriteEdit(pass.Fset, file, issue); ok {
+				suggestedFixes = append(suggestedFixes, analysis.SuggestedFix{
+					Message:   fmt.Sprintf("Rewrite import to `%s`", issue.Rewrite),
+					TextEdits: []analysis.TextEdit{{Pos: edit.Pos, End: edit.End, NewText: edit.NewText}},
+				})
+			}
+
+			pass.Report(analysis.Diagnostic{
+				Pos:            pos,
+				End:            end,
+				Category:       issue.RuleID,
+				Message:        message,
+				SuggestedFixes: suggestedFixes,
+			})
+		}
+	}
+
+	return nil, nil //nolint:nilnil // The analyzer produces no result.
+}
+
+// processor returns the processor for the module that dir belongs to,
+// creating it on first use.
+func (r *runner) processor(dir string) (*gomodguard.Processor, error) {
+	goModFilePath, err := gomodguard.FindGoModFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.loaded {
+		r.config, r.loadErr = r.configFromFlags()
+		r.loaded = true
+	}
+
+	if r.loadErr != nil {
+		return nil, r.loadErr
+	}
+
+	if p, ok := r.processors[goModFilePath]; ok {
+		return p, nil
+	}
+
+	p, err := gomodguard.NewProcessorFromModFile(r.config, goModFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	r.processors[goModFilePath] = p
+
+	return p, nil
+}
+
+// configFromFlags builds the configuration from the config file flag and
+// appends the modules given with the allowed and blocked flags.
+func (r *runner) configFromFlags() (*gomodguard.Configuration, error) {
+	config := &gomodguard.Configuration{}
+
+	if r.configFile != "" {
+		var err error
+
+		config, err = gomodguard.LoadConfiguration(r.configFile, r.policyDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, module := range r.allowed {
+		config.Allowed = append(config.Allowed, gomodguard.AllowedModule{Module: module})
+	}
+
+	for _, module := range r.blocked {
+		config.Blocked = append(config.Blocked, gomodguard.BlockedModule{Module: module})
+	}
+
+	if r.localReplaceDirectives {
+		config.LocalReplaceDirectives = true
+	}
+
+	return config, nil
+}
+
+// stringList is a flag.Value holding a comma separated list of strings.
+type stringList []string
+
+var _ flag.Value = (*stringList)(nil)
+
+func (s *stringList) String() string {
+	if s == nil {
+		return ""
+	}
+
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for v := range strings.SplitSeq(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return errors.New("empty module name")
+		}
+
+		*s = append(*s, v)
+	}
+
+	return nil
+}
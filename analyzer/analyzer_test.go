@@ -0,0 +1,57 @@
+package analyzer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ryancurrah/gomodguard/v2"
+	"github.com/ryancurrah/gomodguard/v2/analyzer"
+)
+
+func TestNewAnalyzer(t *testing.T) {
+	a := analyzer.NewAnalyzer(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:          "example.com/blockedmod",
+				Recommendations: []string{"example.com/allowedmod"},
+				Reason:          "testing the analyzer.",
+			},
+		},
+	})
+
+	analysistest.Run(t, testdataDir(t), a, "./src/...")
+}
+
+func TestAnalyzerConfigFlag(t *testing.T) {
+	a := analyzer.NewAnalyzer(nil)
+	require.NoError(t, a.Flags.Set("config", filepath.Join(testdataDir(t), ".gomodguard.yaml")))
+
+	analysistest.Run(t, testdataDir(t), a, "./src/...")
+}
+
+func TestAnalyzerSuggestedFixes(t *testing.T) {
+	a := analyzer.NewAnalyzer(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:          "example.com/blockedmod",
+				Recommendations: []string{"example.com/allowedmod"},
+				Reason:          "testing the analyzer.",
+				Rewrite:         map[string]string{"example.com/blockedmod": "example.com/allowedmod"},
+			},
+		},
+	})
+
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "./src/b")
+}
+
+func testdataDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	return dir
+}
@@ -0,0 +1,8 @@
+package allowedmod
+
+// Allowed is used by the analyzer test package.
+const Allowed = "allowed"
+
+// Blocked is used by the analyzer test package once its import of the
+// blocked module is rewritten.
+const Blocked = "blocked"
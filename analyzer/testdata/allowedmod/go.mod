@@ -0,0 +1,3 @@
+module example.com/allowedmod
+
+go 1.25.0
@@ -0,0 +1,4 @@
+package blockedmod
+
+// Blocked is used by the analyzer test package.
+const Blocked = "blocked"
@@ -0,0 +1,3 @@
+module example.com/blockedmod
+
+go 1.25.0
@@ -0,0 +1,13 @@
+module example.com/analyzertest
+
+go 1.25.0
+
+require (
+	example.com/allowedmod v0.1.0
+	example.com/blockedmod v0.1.0
+)
+
+replace (
+	example.com/allowedmod => ./allowedmod
+	example.com/blockedmod => ./blockedmod
+)
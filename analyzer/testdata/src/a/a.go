@@ -0,0 +1,8 @@
+package a
+
+import (
+	"example.com/allowedmod"
+	"example.com/blockedmod" // want "import of package `example.com/blockedmod` is blocked because the module is in the blocked modules list. `example.com/allowedmod` is a recommended module. testing the analyzer."
+)
+
+var _ = allowedmod.Allowed + blockedmod.Blocked
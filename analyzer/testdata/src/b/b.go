@@ -0,0 +1,10 @@
+package b
+
+import (
+	"fmt"
+
+	// The blocked module is rewritten to its replacement.
+	"example.com/blockedmod" // want "import of package `example.com/blockedmod` is blocked because the module is in the blocked modules list. `example.com/allowedmod` is a recommended module. testing the analyzer."
+)
+
+var _ = fmt.Sprint(blockedmod.Blocked)
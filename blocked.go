@@ -12,12 +12,35 @@ type Blocked []BlockedModule
 
 // BlockedModule is a single entry in the blocked list.
 type BlockedModule struct {
+	// ID identifies the rule in issues and reports. When omitted it is derived
+	// from Module, see RuleID.
+	ID              string              `yaml:"id,omitempty"`
 	Module          string              `yaml:"module"`
 	MatchType       MatchType           `yaml:"match-type"`
 	Recommendations []string            `yaml:"recommendations"`
 	Reason          string              `yaml:"reason"`
 	Version         *semver.Constraints `yaml:"version"`
-	Matcher         Matcher             `yaml:"-"`
+	// Severity is the severity of the issues for the module, it defaults to
+	// error.
+	Severity Severity `yaml:"severity,omitempty"`
+	// AllowUntil waives the rule until the end of the day, the module is
+	// blocked afterwards.
+	AllowUntil *Date `yaml:"allow-until,omitempty"`
+	// Rewrite maps import paths of the module, or their parent paths, to the
+	// import paths that -fix rewrites the imports to, e.g. github.com/pkg/errors
+	// to errors.
+	Rewrite map[string]string `yaml:"rewrite,omitempty"`
+	Matcher Matcher           `yaml:"-"`
+}
+
+// RuleID returns the ID of the rule, or an ID derived from the module when
+// none is configured, e.g. "blocked/github.com/foo/bar".
+func (r *BlockedModule) RuleID() string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return deriveRuleID(blockedRuleIDPrefix, r.Module)
 }
 
 // CheckVersion returns true if the module version matches the blocked constraint.
@@ -97,3 +120,19 @@ func (r *BlockedModule) HasRecommendations() bool {
 
 	return len(r.Recommendations) > 0
 }
+
+// blockedModule returns why a module matched by the rule with the given key is blocked.
+func (r *BlockedModule) blockedModule(key, moduleName, moduleVersion, reason string) blockedModule {
+	return blockedModule{
+		reason:          reason,
+		kind:            IssueKindBlocked,
+		module:          moduleName,
+		version:         moduleVersion,
+		rule:            key,
+		ruleID:          r.RuleID(),
+		matchType:       r.MatchType.orDefault(),
+		recommendations: r.Recommendations,
+		severity:        r.Severity.orDefault(),
+		rewrite:         r.Rewrite,
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+const (
+	errReadingBaselineFile = "could not read baseline file: %w"
+	errParsingBaselineFile = "could not parse baseline file: %w"
+)
+
+// Baseline records existing issues so that only new issues fail the lint.
+// Issues are keyed by file, imported package and rule ID rather than by line
+// number so unrelated edits to a file do not invalidate the baseline.
+type Baseline struct {
+	Issues []BaselineEntry `json:"issues"`
+}
+
+// BaselineEntry is the number of issues recorded for a file, imported
+// package and rule ID.
+type BaselineEntry struct {
+	File    string `json:"file"`
+	Package string `json:"package,omitempty"`
+	RuleID  string `json:"rule_id,omitempty"`
+	Count   int    `json:"count"`
+}
+
+// baselineKey identifies the issues a baseline entry records.
+type baselineKey struct {
+	file   string
+	pkg    string
+	ruleID string
+}
+
+func newBaselineKey(issue gomodguard.Issue) baselineKey {
+	return baselineKey{
+		file:   filepath.ToSlash(issue.FileName),
+		pkg:    issue.Package,
+		ruleID: issue.RuleID,
+	}
+}
+
+// NewBaseline returns a baseline recording the given results.
+func NewBaseline(results []gomodguard.Issue) *Baseline {
+	counts := make(map[baselineKey]int, len(results))
+	for i := range results {
+		counts[newBaselineKey(results[i])]++
+	}
+
+	baseline := &Baseline{Issues: make([]BaselineEntry, 0, len(counts))}
+	for key, count := range counts {
+		baseline.Issues = append(baseline.Issues, BaselineEntry{
+			File:    key.file,
+			Package: key.pkg,
+			RuleID:  key.ruleID,
+			Count:   count,
+		})
+	}
+
+	slices.SortFunc(baseline.Issues, func(a, b BaselineEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.RuleID, b.RuleID),
+		)
+	})
+
+	return baseline
+}
+
+// ReadBaseline reads a baseline file written by WriteBaseline.
+func ReadBaseline(baselineFilePath string) (*Baseline, error) {
+	data, err := os.ReadFile(filepath.Clean(baselineFilePath))
+	if err != nil {
+		return nil, fmt.Errorf(errReadingBaselineFile, err)
+	}
+
+	var baseline Baseline
+
+	err = json.Unmarshal(data, &baseline)
+	if err != nil {
+		return nil, fmt.Errorf(errParsingBaselineFile, err)
+	}
+
+	return &baseline, nil
+}
+
+// WriteBaseline takes the results and writes them to a baseline file.
+func WriteBaseline(baselineFilePath string, results []gomodguard.Issue) error {
+	body, err := json.MarshalIndent(NewBaseline(results), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(baselineFilePath, append(body, '\n'), 0644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Filter returns the results that are not recorded in the baseline and the
+// baseline entries that no longer occur in the results. When an entry occurs
+// less often than recorded, it is returned with the number of missing issues
+// as its count.
+func (b *Baseline) Filter(results []gomodguard.Issue) ([]gomodguard.Issue, []BaselineEntry) {
+	remaining := make(map[baselineKey]int, len(b.Issues))
+	for _, entry := range b.Issues {
+		remaining[baselineKey{file: entry.File, pkg: entry.Package, ruleID: entry.RuleID}] += entry.Count
+	}
+
+	newIssues := []gomodguard.Issue{}
+
+	for i := range results {
+		key := newBaselineKey(results[i])
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+
+		newIssues = append(newIssues, results[i])
+	}
+
+	stale := []BaselineEntry{}
+
+	for _, entry := range b.Issues {
+		key := baselineKey{file: entry.File, pkg: entry.Package, ruleID: entry.RuleID}
+		if remaining[key] > 0 {
+			stale = append(stale, BaselineEntry{
+				File:    entry.File,
+				Package: entry.Package,
+				RuleID:  entry.RuleID,
+				Count:   remaining[key],
+			})
+
+			remaining[key] = 0
+		}
+	}
+
+	return newIssues, stale
+}
@@ -0,0 +1,57 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestBaseline(t *testing.T) {
+	baselineFile := t.TempDir() + "/baseline.json"
+
+	recorded := []gomodguard.Issue{
+		{FileName: "a.go", LineNumber: 3, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		{FileName: "a.go", LineNumber: 4, Package: "github.com/foo/baz", RuleID: "not-allowed"},
+		{FileName: "b.go", LineNumber: 5, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		{FileName: "b.go", LineNumber: 9, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+	}
+
+	require.NoError(t, cli.WriteBaseline(baselineFile, recorded))
+
+	baseline, err := cli.ReadBaseline(baselineFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []cli.BaselineEntry{
+		{File: "a.go", Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar", Count: 1},
+		{File: "a.go", Package: "github.com/foo/baz", RuleID: "not-allowed", Count: 1},
+		{File: "b.go", Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar", Count: 2},
+	}, baseline.Issues)
+
+	current := []gomodguard.Issue{
+		// Moved to another line, still recorded.
+		{FileName: "a.go", LineNumber: 10, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		// One of two recorded issues remains.
+		{FileName: "b.go", LineNumber: 5, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		// New issues.
+		{FileName: "a.go", LineNumber: 11, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		{FileName: "c.go", LineNumber: 3, Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+	}
+
+	newIssues, stale := baseline.Filter(current)
+
+	assert.Equal(t, []gomodguard.Issue{current[2], current[3]}, newIssues)
+	assert.Equal(t, []cli.BaselineEntry{
+		{File: "a.go", Package: "github.com/foo/baz", RuleID: "not-allowed", Count: 1},
+		{File: "b.go", Package: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar", Count: 1},
+	}, stale)
+}
+
+func TestReadBaselineMissingFile(t *testing.T) {
+	_, err := cli.ReadBaseline(t.TempDir() + "/missing.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read baseline file")
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"encoding/xml"
 	"errors"
 	"flag"
 	"fmt"
@@ -13,22 +12,28 @@ import (
 	"strings"
 
 	"github.com/mitchellh/go-homedir"
-	"github.com/phayes/checkstyle"
-	"go.yaml.in/yaml/v4"
 
 	"github.com/ryancurrah/gomodguard/v2"
 )
 
 const (
-	errFindingHomedir    = "unable to find home directory, %w"
-	errReadingConfigFile = "could not read config file: %w"
-	errParsingConfigFile = "could not parse config file: %w"
+	errFindingHomedir = "unable to find home directory, %w"
+
+	configFileEnvVar = "GOMODGUARD_CONFIG"
+
+	levelImport = "import"
+	levelGoMod  = "gomod"
+	levelBoth   = "both"
 )
 
 var (
 	configFile           = ".gomodguard.yaml"
 	logger               = log.New(os.Stderr, "", 0)
 	errFindingConfigFile = errors.New("could not find config file")
+
+	// rootMarkers are the files and directories that mark the root of a
+	// repository or module, the search for a config file stops there.
+	rootMarkers = []string{".git", ".hg", ".svn", "go.mod"}
 )
 
 // Run the gomodguard linter. Returns the exit code to use.
@@ -39,6 +44,22 @@ func Run() int {
 		return MigrateConfig(configFile)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		return Validate(os.Args[2:])
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		return Init(os.Args[2:])
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "explain" {
+		return Explain(os.Args[2:])
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "schema" {
+		return PrintSchema()
+	}
+
 	var (
 		args           []string
 		help           bool
@@ -46,7 +67,16 @@ func Run() int {
 		report         string
 		reportFile     string
 		issuesExitCode int
+		baselineFile   string
+		baselineWrite  string
+		level          string
+		configPath     string
+		policyDir      string
 		printVersion   bool
+		reportUnused   bool
+		fix            bool
+		diff           bool
+		fixGoMod       bool
 		cwd, _         = os.Getwd()
 	)
 
@@ -55,13 +85,28 @@ func Run() int {
 	flag.BoolVar(&help, "help", false, "")
 	flag.BoolVar(&noTest, "n", false, "Don't lint test files")
 	flag.BoolVar(&noTest, "no-test", false, "")
-	flag.StringVar(&report, "r", "", "Report results to one of the following formats: checkstyle. "+
-		"A report file destination must also be specified")
+	flag.StringVar(&report, "r", "", "Report results to one of the following formats: "+
+		strings.Join(ReportTypes(), ", ")+". A report file destination must also be specified")
 	flag.StringVar(&report, "report", "", "")
 	flag.StringVar(&reportFile, "f", "", "Report results to the specified file. A report type must also be specified")
 	flag.StringVar(&reportFile, "file", "", "")
 	flag.IntVar(&issuesExitCode, "i", 2, "Exit code when issues were found")
 	flag.IntVar(&issuesExitCode, "issues-exit-code", 2, "")
+	flag.StringVar(&baselineFile, "baseline", "", "Only report issues that are not recorded in the specified baseline file")
+	flag.StringVar(&baselineWrite, "baseline-write", "", "Record the current issues to the specified baseline file and exit")
+	flag.StringVar(&configPath, "config", "", "Path to the config file, overrides the "+configFileEnvVar+
+		" environment variable and the search for a "+configFile+" file")
+	flag.StringVar(&policyDir, "policy-dir", "", "Directory to look up extended config files in when they are not "+
+		"found relative to the extending file, overrides the "+gomodguard.PolicyDirEnvVar+" environment variable")
+	flag.StringVar(&level, "level", levelImport, "Report blocked modules at the imports of their packages, at their "+
+		"require lines in go.mod or both: "+strings.Join([]string{levelImport, levelGoMod, levelBoth}, ", "))
+	flag.BoolVar(&reportUnused, "report-unused-rules", false, "Warn about allowed and blocked rules that match no "+
+		"requirement in go.mod, so stale rules can be pruned")
+	flag.BoolVar(&fix, "fix", false, "Rewrite the imports of blocked modules with a rewrite mapping before linting")
+	flag.BoolVar(&fixGoMod, "fix-gomod", false, "Bump blocked requirements in go.mod to the lowest version that is not "+
+		"blocked, found in the module cache or the file:// directories of GOPROXY, and drop blocked requirements that "+
+		"are no longer imported, before linting")
+	flag.BoolVar(&diff, "diff", false, "Print the import rewrites of -fix as a unified diff without applying them and exit")
 	flag.Parse()
 
 	if printVersion {
@@ -84,7 +129,8 @@ func Run() int {
 		return 0
 	}
 
-	if report != "" && report != "checkstyle" {
+	writeReport, ok := reportWriters[report]
+	if report != "" && !ok {
 		logger.Fatalf("error: invalid report type '%s'", report)
 	}
 
@@ -96,19 +142,39 @@ func Run() int {
 		logger.Fatalf("error: a report type must be specified when a report file is enabled")
 	}
 
+	level = strings.TrimSpace(strings.ToLower(level))
+	if level != levelImport && level != levelGoMod && level != levelBoth {
+		logger.Fatalf("error: invalid level '%s'", level)
+	}
+
+	if baselineFile != "" && baselineWrite != "" {
+		logger.Fatalf("error: a baseline file cannot be used and written at the same time")
+	}
+
+	if fix && diff {
+		logger.Fatalf("error: imports cannot be fixed and diffed at the same time")
+	}
+
 	args = flag.Args()
 	if len(args) == 0 {
 		args = []string{"./..."}
 	}
 
-	config, err := getConfig(configFile)
+	cfgFile, err := FindConfigFile(configPath, cwd)
+	if err != nil {
+		logger.Fatalf("error: %s", err)
+	}
+
+	logger.Printf("info: using config file %s", cfgFile)
+
+	config, err := gomodguard.LoadConfiguration(cfgFile, policyDir)
 	if err != nil {
 		logger.Fatalf("error: %s", err)
 	}
 
 	filteredFiles := gomodguard.Find(cwd, noTest, args)
 
-	processor, err := gomodguard.NewProcessor(config)
+	processor, err := newProcessor(config, cwd)
 	if err != nil {
 		logger.Fatalf("error: %s", err)
 	}
@@ -129,10 +195,79 @@ func Run() int {
 	logger.Printf("info: allowed modules, %+v", allowedModuleNames)
 	logger.Printf("info: blocked modules, %+v", blockedModuleNames)
 
-	results := processor.ProcessFiles(filteredFiles)
+	if diff {
+		err := WriteFixesDiff(os.Stdout, processor.FixFiles(filteredFiles))
+		if err != nil {
+			logger.Fatalf("error: %s", err)
+		}
 
-	if report == "checkstyle" {
-		err := WriteCheckstyle(reportFile, results)
+		return 0
+	}
+
+	if fix {
+		fixes := processor.FixFiles(filteredFiles)
+
+		err := ApplyFixes(fixes)
+		if err != nil {
+			logger.Fatalf("error: %s", err)
+		}
+
+		if len(fixes) > 0 {
+			logger.Printf("info: rewrote the imports of %d files, run `go mod tidy` to update go.mod", len(fixes))
+		}
+	}
+
+	if fixGoMod {
+		processor = fixModFiles(processor, config, cwd)
+	}
+
+	var results []gomodguard.Issue
+
+	if level != levelGoMod {
+		results = processor.ProcessFiles(filteredFiles)
+	}
+
+	if level != levelImport {
+		results = append(results, processor.ProcessRequires()...)
+	}
+
+	results = append(results, processor.ProcessModFile()...)
+
+	if reportUnused {
+		for _, r := range processor.UnusedRules() {
+			logger.Printf("warning: %s rule `%s` (%s) matches no requirement in go.mod", r.List, r.Module, r.RuleID)
+		}
+	}
+
+	if baselineWrite != "" {
+		err := WriteBaseline(baselineWrite, results)
+		if err != nil {
+			logger.Fatalf("error: %s", err)
+		}
+
+		logger.Printf("info: recorded %d issues to baseline file %s", len(results), baselineWrite)
+
+		return 0
+	}
+
+	if baselineFile != "" {
+		baseline, err := ReadBaseline(baselineFile)
+		if err != nil {
+			logger.Fatalf("error: %s", err)
+		}
+
+		var stale []BaselineEntry
+
+		results, stale = baseline.Filter(results)
+
+		for _, entry := range stale {
+			logger.Printf("info: baseline entry no longer occurs and can be removed, file: %s, package: %s, rule: %s, count: %d",
+				entry.File, entry.Package, entry.RuleID, entry.Count)
+		}
+	}
+
+	if writeReport != nil {
+		err := writeReport(reportFile, config, results)
 		if err != nil {
 			logger.Fatalf("error: %s", err)
 		}
@@ -142,46 +277,111 @@ func Run() int {
 		fmt.Println(r.String())
 	}
 
-	if len(results) > 0 {
+	if slices.ContainsFunc(results, func(r gomodguard.Issue) bool { return r.IsError() }) {
 		return issuesExitCode
 	}
 
 	return 0
 }
 
-// getConfig from YAML file.
-func getConfig(configFile string) (*gomodguard.Configuration, error) {
-	config := gomodguard.Configuration{}
+// processor lints the modules of the working directory, it is implemented by
+// gomodguard.Processor and gomodguard.Workspace.
+type processor interface {
+	ProcessFiles(filenames []string) []gomodguard.Issue
+	ProcessRequires() []gomodguard.Issue
+	ProcessModFile() []gomodguard.Issue
+	UnusedRules() []gomodguard.UnusedRule
+	FixFiles(filenames []string) []gomodguard.FileFix
+	FixModFiles() ([]gomodguard.ModFileFix, error)
+}
 
-	home, err := homedir.Dir()
+// newProcessor returns a processor for the go.work workspace of the working
+// directory, or for its module when it is not in a workspace or its module is
+// not used by the workspace.
+func newProcessor(config *gomodguard.Configuration, cwd string) (processor, error) {
+	goWorkFilePath := gomodguard.FindGoWorkFile()
+	if goWorkFilePath == "" {
+		return gomodguard.NewProcessor(config)
+	}
+
+	workspace, err := gomodguard.NewWorkspace(config, goWorkFilePath)
 	if err != nil {
-		return nil, fmt.Errorf(errFindingHomedir, err)
+		return nil, err
 	}
 
-	homeDirCfgFile := filepath.Join(home, configFile)
+	// A module below the workspace directory that the workspace does not use
+	// is linted on its own, like the go command does with GOWORK=off.
+	goModFilePath, err := gomodguard.FindGoModFile(cwd)
+	if err == nil && workspace.Processor(goModFilePath) == nil && isWithinDir(goModFilePath, filepath.Dir(goWorkFilePath)) {
+		return gomodguard.NewProcessor(config)
+	}
 
-	var cfgFile string
+	logger.Printf("info: linting the modules of workspace %s", goWorkFilePath)
 
-	switch {
-	case fileExists(configFile):
-		cfgFile = configFile
-	case fileExists(homeDirCfgFile):
-		cfgFile = homeDirCfgFile
-	default:
-		return nil, fmt.Errorf("%w: %s %s", errFindingConfigFile, configFile, homeDirCfgFile)
+	return workspace, nil
+}
+
+// FindConfigFile returns the path of the config file to use. In order of
+// precedence that is configPath, the path in the GOMODGUARD_CONFIG environment
+// variable, the nearest .gomodguard.yaml file in dir or its parent directories
+// up to the root of the repository or module, and the .gomodguard.yaml file in
+// the home directory.
+func FindConfigFile(configPath, dir string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+
+	if envPath := os.Getenv(configFileEnvVar); envPath != "" {
+		return envPath, nil
 	}
 
-	data, err := os.ReadFile(filepath.Clean(cfgFile))
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		return nil, fmt.Errorf(errReadingConfigFile, err)
+		return "", err
+	}
+
+	searched := []string{}
+
+	for {
+		cfgFile := filepath.Join(dir, configFile)
+		if fileExists(cfgFile) {
+			return cfgFile, nil
+		}
+
+		searched = append(searched, cfgFile)
+
+		parent := filepath.Dir(dir)
+		if isRootDir(dir) || parent == dir {
+			break
+		}
+
+		dir = parent
 	}
 
-	err = yaml.Unmarshal(data, &config)
+	home, err := homedir.Dir()
 	if err != nil {
-		return nil, fmt.Errorf(errParsingConfigFile, err)
+		return "", fmt.Errorf(errFindingHomedir, err)
 	}
 
-	return &config, nil
+	homeDirCfgFile := filepath.Join(home, configFile)
+	if fileExists(homeDirCfgFile) {
+		return homeDirCfgFile, nil
+	}
+
+	searched = append(searched, homeDirCfgFile)
+
+	return "", fmt.Errorf("%w: %s", errFindingConfigFile, strings.Join(searched, " "))
+}
+
+// isRootDir returns true if dir is the root of a repository or module.
+func isRootDir(dir string) bool {
+	for _, marker := range rootMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+
+	return false
 }
 
 // showHelp text for command line.
@@ -191,43 +391,22 @@ Also supports package syntax but will use it in relative path, i.e. ./pkg/...
 
 Commands:
   (default)  Lint Go module dependencies using the configuration file
+  explain    Show which rules match a module and why it is blocked or not: explain <module> [version]
+  init       Write a config file allowing every direct requirement of go.mod: init [-prefixes] [-pin-versions] [-output file] [-force]
   migrate    Convert a v1 .gomodguard.yaml config file to v2 format and print to stdout
+  schema     Print the JSON Schema of the config file
+  validate   Strictly check the config file, or the one given as argument, and print its errors and warnings
 
 Flags:`
 	fmt.Println(helpText)
 	flag.PrintDefaults()
 }
 
-// WriteCheckstyle takes the results and writes them to a checkstyle formated file.
-func WriteCheckstyle(checkstyleFilePath string, results []gomodguard.Issue) error {
-	check := checkstyle.New()
-
-	for i := range results {
-		file := check.EnsureFile(results[i].FileName)
-		file.AddError(
-			checkstyle.NewError(
-				results[i].LineNumber, 1,
-				checkstyle.SeverityError,
-				results[i].Reason,
-				"gomodguard",
-			),
-		)
-	}
-
-	body, err := xml.MarshalIndent(check, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	header := []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>")
-	checkstyleXML := slices.Concat([]byte{'\n'}, header, []byte{'\n'}, body)
-
-	err = os.WriteFile(checkstyleFilePath, checkstyleXML, 0644) //nolint:gosec
-	if err != nil {
-		return err
-	}
+// isWithinDir returns true if path is inside dir.
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
 
-	return nil
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // fileExists returns true if the file path provided exists.
@@ -2,8 +2,10 @@ package cli_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/mitchellh/go-homedir"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -36,11 +38,13 @@ func TestWriteCheckstyle(t *testing.T) {
 			FileName:   "first.go",
 			LineNumber: 10,
 			Reason:     "first test reason",
+			RuleID:     "blocked/github.com/foo/bar",
 		},
 		{
 			FileName:   "second.go",
 			LineNumber: 20,
 			Reason:     "second test reason",
+			Severity:   gomodguard.SeverityWarning,
 		},
 	}
 
@@ -54,11 +58,98 @@ func TestWriteCheckstyle(t *testing.T) {
 <?xml version="1.0" encoding="UTF-8"?>
 <checkstyle version="1.0.0">
   <file name="first.go">
-    <error line="10" column="1" severity="error" message="first test reason" source="gomodguard"></error>
+    <error line="10" column="1" severity="error" message="first test reason" source="gomodguard:blocked/github.com/foo/bar"></error>
   </file>
   <file name="second.go">
-    <error line="20" column="1" severity="error" message="second test reason" source="gomodguard"></error>
+    <error line="20" column="1" severity="warning" message="second test reason" source="gomodguard"></error>
   </file>
 </checkstyle>`
 	assert.Equal(t, want, string(got))
 }
+
+func TestFindConfigFile(t *testing.T) {
+	disableCache := homedir.DisableCache
+	homedir.DisableCache = true
+
+	t.Cleanup(func() { homedir.DisableCache = disableCache })
+
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	homeDir := filepath.Join(tmpDir, "home")
+
+	for _, dir := range []string{
+		filepath.Join(repoDir, ".git"),
+		filepath.Join(repoDir, "pkg", "sub"),
+		filepath.Join(repoDir, "mod", "pkg"),
+		filepath.Join(tmpDir, "norepo"),
+		homeDir,
+	} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+
+	for _, file := range []string{
+		filepath.Join(repoDir, ".gomodguard.yaml"),
+		filepath.Join(repoDir, "mod", "go.mod"),
+		filepath.Join(homeDir, ".gomodguard.yaml"),
+	} {
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+	}
+
+	tests := map[string]struct {
+		configPath string
+		envPath    string
+		dir        string
+		noHome     bool
+		want       string
+		wantErr    bool
+	}{
+		"config flag takes precedence": {
+			configPath: "policy.yaml",
+			envPath:    "env.yaml",
+			dir:        repoDir,
+			want:       "policy.yaml",
+		},
+		"environment variable takes precedence over the search": {
+			envPath: "env.yaml",
+			dir:     repoDir,
+			want:    "env.yaml",
+		},
+		"nearest config file in a parent directory": {
+			dir:  filepath.Join(repoDir, "pkg", "sub"),
+			want: filepath.Join(repoDir, ".gomodguard.yaml"),
+		},
+		"search stops at the module root": {
+			dir:  filepath.Join(repoDir, "mod", "pkg"),
+			want: filepath.Join(homeDir, ".gomodguard.yaml"),
+		},
+		"falls back to the home directory": {
+			dir:  filepath.Join(tmpDir, "norepo"),
+			want: filepath.Join(homeDir, ".gomodguard.yaml"),
+		},
+		"no config file": {
+			dir:     filepath.Join(repoDir, "mod", "pkg"),
+			noHome:  true,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GOMODGUARD_CONFIG", tt.envPath)
+			t.Setenv("HOME", homeDir)
+
+			if tt.noHome {
+				t.Setenv("HOME", filepath.Join(tmpDir, "norepo"))
+			}
+
+			got, err := cli.FindConfigFile(tt.configPath, tt.dir)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// Explain prints which allowed and blocked rules match the module given in
+// args, which of them wins and whether the module is blocked. Returns the exit
+// code to use.
+func Explain(args []string) int {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	configPath := flags.String("config", "", "Path to the config file, overrides the "+configFileEnvVar+
+		" environment variable and the search for a "+configFile+" file")
+	policyDir := flags.String("policy-dir", "", "Directory to look up extended config files in when they are not "+
+		"found relative to the extending file, overrides the "+gomodguard.PolicyDirEnvVar+" environment variable")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		logger.Printf("error: usage: gomodguard explain [flags] <module> [version]")
+		return 1
+	}
+
+	cwd, _ := os.Getwd()
+
+	cfgFile, err := FindConfigFile(*configPath, cwd)
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	config, err := gomodguard.LoadConfiguration(cfgFile, *policyDir)
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	processor, err := gomodguard.NewProcessor(config)
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	WriteExplanation(os.Stdout, processor.Explain(flags.Arg(0), flags.Arg(1)))
+
+	return 0
+}
+
+// WriteExplanation writes the explanation in a human readable form.
+func WriteExplanation(w io.Writer, e gomodguard.Explanation) {
+	fmt.Fprintf(w, "module:  %s\n", e.Module)
+
+	switch {
+	case e.Version == "":
+		fmt.Fprintf(w, "version: unknown, not required in go.mod\n")
+	case e.VersionFromModFile:
+		fmt.Fprintf(w, "version: %s (required in go.mod)\n", e.Version)
+	default:
+		fmt.Fprintf(w, "version: %s\n", e.Version)
+	}
+
+	fmt.Fprintf(w, "\nblocked rules, evaluated first:\n")
+	writeRuleList(w, e.BlockedRules, "the rule applies", "the rule does not apply")
+
+	if len(e.BlockedRules.Candidates) > 0 {
+		if e.RecommendationExemption {
+			fmt.Fprintf(w, "  recommendation exemption: applies, `%s` is a recommended module so the rule is skipped\n",
+				e.CurrentModule)
+		} else {
+			fmt.Fprintf(w, "  recommendation exemption: does not apply, `%s` is not a recommended module\n",
+				e.CurrentModule)
+		}
+	}
+
+	fmt.Fprintf(w, "\nallowed rules:\n")
+
+	if !e.AllowedRules.Configured {
+		fmt.Fprintf(w, "  no rules configured, every module that is not blocked is allowed\n")
+	} else {
+		writeRuleList(w, e.AllowedRules, "the module is allowed", "the module is not allowed")
+	}
+
+	switch {
+	case e.Undetermined:
+		fmt.Fprintf(w, "\nresult: depends on the version, give a version to evaluate the version constraints\n")
+	case e.Blocked:
+		fmt.Fprintf(w, "\nresult: blocked because %s (%s)\n", e.Reason, e.RuleID)
+	default:
+		fmt.Fprintf(w, "\nresult: allowed\n")
+	}
+}
+
+// writeRuleList writes the matching rules of a list, marking the winner, and
+// how the version constraint of the winner evaluated.
+func writeRuleList(w io.Writer, list gomodguard.RuleListExplanation, meets, doesNotMeet string) {
+	if len(list.Candidates) == 0 {
+		fmt.Fprintf(w, "  no rule matches\n")
+		return
+	}
+
+	for i, c := range list.Candidates {
+		marker := " "
+		if i == 0 {
+			marker = "*"
+		}
+
+		fmt.Fprintf(w, "  %s tier %d %-6s %s (%s)\n", marker, c.Tier, c.MatchType, c.Rule, c.RuleID)
+	}
+
+	fmt.Fprintf(w, "  the rule marked with * wins: exact rules first, then the longest prefix, then the first regex alphabetically\n")
+
+	version := list.Version
+
+	switch {
+	case version.Constraint == "":
+		fmt.Fprintf(w, "  version: no constraint, %s for every version\n", meets)
+	case version.Err != nil:
+		fmt.Fprintf(w, "  version constraint `%s`: could not be evaluated, %s\n", version.Constraint, version.Err)
+	case !version.Evaluated:
+		fmt.Fprintf(w, "  version constraint `%s`: not evaluated, the version is unknown\n", version.Constraint)
+	case version.Satisfied:
+		fmt.Fprintf(w, "  version constraint `%s`: met, %s\n", version.Constraint, meets)
+	default:
+		fmt.Fprintf(w, "  version constraint `%s`: not met, %s\n", version.Constraint, doesNotMeet)
+	}
+}
@@ -0,0 +1,67 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestWriteExplanation(t *testing.T) {
+	t.Chdir(examplesDir + "alloptions")
+
+	config, err := gomodguard.LoadConfiguration(".gomodguard.yaml", "")
+	require.NoError(t, err)
+
+	processor, err := gomodguard.NewProcessor(config)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		module  string
+		version string
+		want    []string
+	}{
+		"blocked by version": {
+			module: "github.com/mitchellh/go-homedir",
+			want: []string{
+				"version: v1.1.0 (required in go.mod)",
+				"* tier 1 exact  github.com/mitchellh/go-homedir (blocked/github.com/mitchellh/go-homedir)",
+				"version constraint `<=1.1.0`: met, the rule applies",
+				"result: blocked because the module is in the blocked modules list.",
+			},
+		},
+		"allowed by prefix": {
+			module:  "golang.org/x/mod",
+			version: "v0.1.0",
+			want: []string{
+				"version: v0.1.0\n",
+				"blocked rules, evaluated first:\n  no rule matches",
+				"* tier 2 prefix golang.org (allowed/golang.org)",
+				"version: no constraint, the module is allowed for every version",
+				"result: allowed",
+			},
+		},
+		"not allowed": {
+			module: "github.com/foo/bar",
+			want: []string{
+				"version: unknown, not required in go.mod",
+				"result: blocked because the module is not in the allowed modules list. (not-allowed)",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cli.WriteExplanation(&buf, processor.Explain(tt.module, tt.version))
+
+			for _, want := range tt.want {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// diffContextLines is the number of unchanged lines shown around changes.
+const diffContextLines = 3
+
+// ApplyFixes writes the fixed source of each file.
+func ApplyFixes(fixes []gomodguard.FileFix) error {
+	for _, fix := range fixes {
+		err := os.WriteFile(filepath.Clean(fix.FileName), fix.Fixed, 0644) //nolint:gosec
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyModFileFixes writes the fixed go.mod files and logs their changed
+// requirements.
+func ApplyModFileFixes(fixes []gomodguard.ModFileFix) error {
+	for _, fix := range fixes {
+		err := os.WriteFile(filepath.Clean(fix.FileName), fix.Fixed, 0644) //nolint:gosec
+		if err != nil {
+			return err
+		}
+
+		for _, change := range fix.Changes {
+			if change.NewVersion == "" {
+				logger.Printf("info: %s: dropped requirement of module `%s` %s", fix.FileName, change.Module, change.Version)
+				continue
+			}
+
+			logger.Printf("info: %s: bumped requirement of module `%s` from %s to %s",
+				fix.FileName, change.Module, change.Version, change.NewVersion)
+		}
+	}
+
+	return nil
+}
+
+// fixModFiles applies the go.mod fixes of the processor and returns a
+// processor for the fixed go.mod files.
+func fixModFiles(p processor, config *gomodguard.Configuration, cwd string) processor {
+	fixes, err := p.FixModFiles()
+	if err != nil {
+		logger.Fatalf("error: %s", err)
+	}
+
+	if len(fixes) == 0 {
+		return p
+	}
+
+	if err := ApplyModFileFixes(fixes); err != nil {
+		logger.Fatalf("error: %s", err)
+	}
+
+	logger.Print("info: run `go mod tidy` to update go.sum and the indirect requirements")
+
+	p, err = newProcessor(config, cwd)
+	if err != nil {
+		logger.Fatalf("error: %s", err)
+	}
+
+	return p
+}
+
+// WriteFixesDiff writes the fixes as a unified diff. Rewriting an import only
+// replaces its name and path, which are on a single line, so the source and
+// the fixed source have the same lines and the diff pairs them one to one.
+func WriteFixesDiff(w io.Writer, fixes []gomodguard.FileFix) error {
+	for _, fix := range fixes {
+		name := filepath.ToSlash(fix.FileName)
+
+		if _, err := fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", name, name); err != nil {
+			return err
+		}
+
+		if err := writeHunks(w, splitLines(fix.Source), splitLines(fix.Fixed)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeHunks writes the hunks of the changed lines between before and after,
+// which must have the same number of lines.
+func writeHunks(w io.Writer, before, after [][]byte) error {
+	changed := make([]int, 0)
+
+	for i := range before {
+		if !bytes.Equal(before[i], after[i]) {
+			changed = append(changed, i)
+		}
+	}
+
+	for len(changed) > 0 {
+		// A hunk spans the changes whose context lines touch or overlap.
+		last := 0
+		for last+1 < len(changed) && changed[last+1]-changed[last] <= 2*diffContextLines+1 {
+			last++
+		}
+
+		start := max(changed[0]-diffContextLines, 0)
+		end := min(changed[last]+diffContextLines+1, len(before))
+
+		if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start); err != nil {
+			return err
+		}
+
+		var hunk bytes.Buffer
+
+		for i := start; i < end; i++ {
+			if bytes.Equal(before[i], after[i]) {
+				writeDiffLine(&hunk, ' ', before[i])
+				continue
+			}
+
+			writeDiffLine(&hunk, '-', before[i])
+			writeDiffLine(&hunk, '+', after[i])
+		}
+
+		if _, err := w.Write(hunk.Bytes()); err != nil {
+			return err
+		}
+
+		changed = changed[last+1:]
+	}
+
+	return nil
+}
+
+// writeDiffLine writes a line of a hunk with its prefix.
+func writeDiffLine(buf *bytes.Buffer, prefix byte, line []byte) {
+	buf.WriteByte(prefix)
+	buf.Write(line)
+
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		buf.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// splitLines splits src into lines that keep their line endings.
+func splitLines(src []byte) [][]byte {
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,85 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestWriteFixesDiff(t *testing.T) {
+	lines := []string{
+		"package a", "", "import (", `	"fmt"`, `	"github.com/gofrs/uuid"`, ")", "",
+		"func a() {", "\t_ = fmt.Sprint()", "\t_ = fmt.Sprint()", "\t_ = fmt.Sprint()", "\t_ = fmt.Sprint()",
+		"\t_ = fmt.Sprint()", "\t_ = fmt.Sprint()", "}", "", `const b = "github.com/gofrs/uuid"`,
+	}
+	src := strings.Join(lines, "\n")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, cli.WriteFixesDiff(&buf, []gomodguard.FileFix{
+		{
+			FileName: "a.go",
+			Source:   []byte(src),
+			Fixed:    []byte(strings.ReplaceAll(src, "github.com/gofrs/uuid", "github.com/google/uuid")),
+		},
+	}))
+
+	assert.Equal(t, `--- a/a.go
++++ b/a.go
+@@ -2,7 +2,7 @@
+ 
+ import (
+ 	"fmt"
+-	"github.com/gofrs/uuid"
++	"github.com/google/uuid"
+ )
+ 
+ func a() {
+@@ -14,4 +14,4 @@
+ 	_ = fmt.Sprint()
+ }
+ 
+-const b = "github.com/gofrs/uuid"
+\ No newline at end of file
++const b = "github.com/google/uuid"
+\ No newline at end of file
+`, buf.String())
+}
+
+func TestApplyFixes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.go")
+	require.NoError(t, os.WriteFile(filename, []byte("package a\n"), 0o600))
+
+	require.NoError(t, cli.ApplyFixes([]gomodguard.FileFix{
+		{FileName: filename, Source: []byte("package a\n"), Fixed: []byte("package b\n")},
+	}))
+
+	body, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "package b\n", string(body))
+}
+
+func TestApplyModFileFixes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(filename, []byte("module a\n\nrequire example.com/a v1.0.0\n"), 0o600))
+
+	require.NoError(t, cli.ApplyModFileFixes([]gomodguard.ModFileFix{
+		{
+			FileName: filename,
+			Fixed:    []byte("module a\n\nrequire example.com/a v1.1.0\n"),
+			Changes:  []gomodguard.RequireChange{{Module: "example.com/a", Version: "v1.0.0", NewVersion: "v1.1.0"}},
+		},
+	}))
+
+	body, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "module a\n\nrequire example.com/a v1.1.0\n", string(body))
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// starterRule is an allowed rule as written to a generated config file,
+// leaving out the fields that are not set.
+type starterRule struct {
+	Module    string               `yaml:"module"`
+	MatchType gomodguard.MatchType `yaml:"match-type,omitempty"`
+	Version   string               `yaml:"version,omitempty"`
+}
+
+type starterConfig struct {
+	Allowed []starterRule `yaml:"allowed"`
+}
+
+// Init writes a config file allowing every direct requirement of the go.mod
+// file of the current directory. Returns the exit code to use.
+func Init(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	output := flags.String("output", configFile, "File to write the config to, - for stdout")
+	force := flags.Bool("force", false, "Overwrite the output file if it exists")
+	prefixes := flags.Bool("prefixes", false, "Collapse modules sharing an organization, e.g. github.com/foo, "+
+		"into a prefix rule")
+	pinVersions := flags.Bool("pin-versions", false, "Allow only the required version of each module or newer")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{})
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	config := processor.StarterConfiguration(gomodguard.StarterOptions{
+		CollapsePrefixes: *prefixes,
+		PinVersions:      *pinVersions,
+	})
+
+	body, err := MarshalStarterConfiguration(config)
+	if err != nil {
+		logger.Printf("error: generating config: %s", err)
+		return 1
+	}
+
+	if *output == "-" {
+		fmt.Print(string(body))
+		return 0
+	}
+
+	if _, err := os.Stat(*output); !*force && !errors.Is(err, fs.ErrNotExist) {
+		logger.Printf("error: %s already exists, use -force to overwrite it", *output)
+		return 1
+	}
+
+	if err := os.WriteFile(*output, body, 0644); err != nil { //nolint:gosec
+		logger.Printf("error: writing %s: %s", *output, err)
+		return 1
+	}
+
+	logger.Printf("info: wrote %s with %d allowed rules", *output, len(config.Allowed))
+
+	return 0
+}
+
+// MarshalStarterConfiguration returns the YAML of a configuration generated
+// by Processor.StarterConfiguration.
+func MarshalStarterConfiguration(config *gomodguard.Configuration) ([]byte, error) {
+	starter := starterConfig{Allowed: make([]starterRule, 0, len(config.Allowed))}
+
+	for _, r := range config.Allowed {
+		rule := starterRule{Module: r.Module, MatchType: r.MatchType}
+		if r.Version != nil {
+			rule.Version = r.Version.String()
+		}
+
+		starter.Allowed = append(starter.Allowed, rule)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(starter); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,48 @@
+package cli_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestInit(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.WriteFile("go.mod", []byte(`module example.com/service
+
+go 1.25.0
+
+require (
+	github.com/foo/bar v1.2.0
+	github.com/foo/baz v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+`), 0o600))
+
+	require.Equal(t, 0, cli.Init([]string{"-prefixes", "-pin-versions"}))
+
+	body, err := os.ReadFile(".gomodguard.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `allowed:
+  - module: github.com/foo/
+    match-type: prefix
+  - module: gopkg.in/yaml.v3
+    version: '>=3.0.1'
+`, string(body))
+
+	config, err := gomodguard.LoadConfiguration(".gomodguard.yaml", "")
+	require.NoError(t, err)
+
+	processor, err := gomodguard.NewProcessor(config)
+	require.NoError(t, err)
+	assert.Empty(t, processor.ProcessRequires())
+
+	assert.Equal(t, 1, cli.Init(nil), "existing config file must not be overwritten")
+	assert.Equal(t, 0, cli.Init([]string{"-force"}))
+}
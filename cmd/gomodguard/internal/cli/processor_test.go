@@ -61,20 +61,20 @@ func TestProcessorYAMLConfig(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the " +
 					"module is in the blocked modules list. `golang.org/x/mod` is a recommended module. `mod` " +
-					"is the official go.mod parser library.",
+					"is the official go.mod parser library. (blocked/github.com/uudashr/go-module)",
 				"blocked_example.go:7:1 import of package `github.com/mitchellh/go-homedir` is blocked because " +
 					"the module is in the blocked modules list. version `v1.1.0` is blocked because it does not " +
-					"meet the version constraint `<=1.1.0`. testing if blocked version constraint works.",
+					"meet the version constraint `<=1.1.0`. testing if blocked version constraint works. (blocked/github.com/mitchellh/go-homedir)",
 				"blocked_example.go:6:1 import of package `github.com/gofrs/uuid` is blocked because the " +
 					"module is in the blocked modules list. `github.com/ryancurrah/gomodguard` is a recommended " +
-					"module. testing if module is not blocked when it is recommended.",
+					"module. testing if module is not blocked when it is recommended. (blocked/github.com/gofrs/uuid)",
 			},
 		},
 		"allowed version - blocked by version constraint": {
 			exampleDir: examplesDir + "allowedversion",
 			wantReasons: []string{
 				"example.go:3:1 import of package `github.com/Masterminds/semver/v3` is blocked because " +
-					"version `v3.1.0` does not meet the allowed version constraint `>=3.2.0`.",
+					"version `v3.1.0` does not meet the allowed version constraint `>=3.2.0`. (allowed/github.com/Masterminds/semver/v3)",
 			},
 		},
 		"allowed version - invalid constraint is caught at parse time": {
@@ -91,9 +91,9 @@ func TestProcessorYAMLConfig(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"indirect_example.go:9:1 import of package `github.com/uudashr/go-module` is blocked because the " +
 					"module is in the blocked modules list. `golang.org/x/mod` is a recommended module. `mod` " +
-					"is the official go.mod parser library.",
+					"is the official go.mod parser library. (blocked/github.com/uudashr/go-module)",
 				"indirect_example.go:6:1 import of package `github.com/gofrs/uuid` is blocked because the " +
-					"module is in the blocked modules list. testing blocked indirect dependency.",
+					"module is in the blocked modules list. testing blocked indirect dependency. (blocked/github.com/gofrs/uuid)",
 			},
 		},
 		"blocked invalid constraint is caught at parse time": {
@@ -104,7 +104,7 @@ func TestProcessorYAMLConfig(t *testing.T) { //nolint:funlen
 			exampleDir: examplesDir + "regextest",
 			wantReasons: []string{
 				"test.go:3:1 import of package `golang.org/x/mod/modfile` is blocked because the " +
-					"module is in the blocked modules list. testing regex based blocking.",
+					"module is in the blocked modules list. testing regex based blocking. (blocked/golang\\.org/x/.*)",
 			},
 		},
 		"regex version - blocked": {
@@ -112,7 +112,19 @@ func TestProcessorYAMLConfig(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"test.go:3:1 import of package `golang.org/x/mod/modfile` is blocked because the " +
 					"module is in the blocked modules list. version `v0.16.0` is blocked because it does not " +
-					"meet the version constraint `<=0.16.0`. testing regex blocking with version constraint.",
+					"meet the version constraint `<=0.16.0`. testing regex blocking with version constraint. (blocked/golang\\.org/x/.*)",
+			},
+		},
+		"suppression comments - suppressed imports are not reported": {
+			exampleDir: examplesDir + "suppression",
+			wantReasons: []string{
+				"example.go:8:1 suppression comment must give a reason, e.g. `//gomodguard:ignore [rule-id] reason`. " +
+					"(suppression-without-reason)",
+				"example.go:9:1 suppression comment does not suppress any issue. (unused-suppression)",
+			},
+			notWantReasons: []string{
+				"import of package `github.com/gofrs/uuid`",
+				"import of package `github.com/mitchellh/go-homedir`",
 			},
 		},
 		"major version module is not blocked by base module rule": {
@@ -120,7 +132,7 @@ func TestProcessorYAMLConfig(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"example.go:4:1 import of package `github.com/gofrs/uuid` is blocked because the " +
 					"module is in the blocked modules list. `github.com/gofrs/uuid/v5` is a recommended " +
-					"module. testing that a major version module is not blocked by a rule targeting the base module.",
+					"module. testing that a major version module is not blocked by a rule targeting the base module. (blocked/github.com/gofrs/uuid)",
 			},
 			notWantReasons: []string{
 				"import of package `github.com/gofrs/uuid/v5`",
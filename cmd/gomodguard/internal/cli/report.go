@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"slices"
+
+	"github.com/phayes/checkstyle"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// ReportWriter writes the results to a report file of a specific format.
+// The configuration the results were produced with is provided for formats
+// that describe the configured rules.
+type ReportWriter func(reportFilePath string, config *gomodguard.Configuration, results []gomodguard.Issue) error
+
+// reportWriters maps each report type accepted by the report flag to its writer.
+var reportWriters = map[string]ReportWriter{
+	"checkstyle": func(reportFilePath string, _ *gomodguard.Configuration, results []gomodguard.Issue) error {
+		return WriteCheckstyle(reportFilePath, results)
+	},
+	"json": func(reportFilePath string, _ *gomodguard.Configuration, results []gomodguard.Issue) error {
+		return WriteJSON(reportFilePath, results)
+	},
+	"sarif": WriteSARIF,
+}
+
+// RegisterReportWriter makes a report writer available under the given report type.
+// Registering a report type that already exists replaces its writer.
+func RegisterReportWriter(reportType string, writer ReportWriter) {
+	reportWriters[reportType] = writer
+}
+
+// ReportTypes returns the sorted names of the registered report types.
+func ReportTypes() []string {
+	reportTypes := make([]string, 0, len(reportWriters))
+	for reportType := range reportWriters {
+		reportTypes = append(reportTypes, reportType)
+	}
+
+	slices.Sort(reportTypes)
+
+	return reportTypes
+}
+
+// WriteCheckstyle takes the results and writes them to a checkstyle formated file.
+func WriteCheckstyle(checkstyleFilePath string, results []gomodguard.Issue) error {
+	check := checkstyle.New()
+
+	for i := range results {
+		file := check.EnsureFile(results[i].FileName)
+		file.AddError(
+			checkstyle.NewError(
+				results[i].LineNumber, 1,
+				checkstyle.Severity(results[i].EffectiveSeverity()),
+				results[i].Reason,
+				checkstyleSource(results[i]),
+			),
+		)
+	}
+
+	body, err := xml.MarshalIndent(check, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	header := []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>")
+	checkstyleXML := slices.Concat([]byte{'\n'}, header, []byte{'\n'}, body)
+
+	err = os.WriteFile(checkstyleFilePath, checkstyleXML, 0644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkstyleSource returns the checkstyle source of the issue, which includes
+// the rule ID so issues can be filtered per rule.
+func checkstyleSource(issue gomodguard.Issue) string {
+	if issue.RuleID == "" {
+		return "gomodguard"
+	}
+
+	return "gomodguard:" + issue.RuleID
+}
+
+// jsonReport is the document written by WriteJSON.
+type jsonReport struct {
+	Issues []jsonIssue `json:"issues"`
+}
+
+// jsonIssue is a single issue of a JSON report.
+type jsonIssue struct {
+	File            string   `json:"file"`
+	Line            int      `json:"line"`
+	Column          int      `json:"column"`
+	Kind            string   `json:"kind,omitempty"`
+	Severity        string   `json:"severity"`
+	Package         string   `json:"package,omitempty"`
+	Module          string   `json:"module,omitempty"`
+	Version         string   `json:"version,omitempty"`
+	Rule            string   `json:"rule,omitempty"`
+	RuleID          string   `json:"rule_id,omitempty"`
+	MatchType       string   `json:"match_type,omitempty"`
+	Recommendations []string `json:"recommendations,omitempty"`
+	DependencyPath  []string `json:"dependency_path,omitempty"`
+	Rewrite         string   `json:"rewrite,omitempty"`
+	Reason          string   `json:"reason"`
+}
+
+// WriteJSON takes the results and writes them to a JSON formatted file.
+func WriteJSON(jsonFilePath string, results []gomodguard.Issue) error {
+	report := jsonReport{Issues: make([]jsonIssue, 0, len(results))}
+
+	for i := range results {
+		column := results[i].Position.Column
+		if column == 0 {
+			column = 1
+		}
+
+		report.Issues = append(report.Issues, jsonIssue{
+			File:            results[i].FileName,
+			Line:            results[i].LineNumber,
+			Column:          column,
+			Kind:            string(results[i].Kind),
+			Severity:        string(results[i].EffectiveSeverity()),
+			Package:         results[i].Package,
+			Module:          results[i].Module,
+			Version:         results[i].Version,
+			Rule:            results[i].Rule,
+			RuleID:          results[i].RuleID,
+			MatchType:       string(results[i].MatchType),
+			Recommendations: results[i].Recommendations,
+			DependencyPath:  results[i].DependencyPath,
+			Rewrite:         results[i].Rewrite,
+			Reason:          results[i].Reason,
+		})
+	}
+
+	body, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(jsonFilePath, append(body, '\n'), 0644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
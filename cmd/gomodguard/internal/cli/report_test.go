@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestReportTypes(t *testing.T) {
+	assert.Equal(t, []string{"checkstyle", "json", "sarif"}, cli.ReportTypes())
+}
+
+func TestWriteJSON(t *testing.T) {
+	outFile := t.TempDir() + "/report.json"
+
+	issues := []gomodguard.Issue{
+		{
+			FileName:        "first.go",
+			LineNumber:      10,
+			Reason:          "first test reason",
+			Kind:            gomodguard.IssueKindBlocked,
+			Package:         "github.com/foo/bar/baz",
+			Module:          "github.com/foo/bar",
+			Version:         "v1.0.0",
+			Rule:            "github.com/foo",
+			RuleID:          "blocked/github.com/foo",
+			MatchType:       gomodguard.PrefixMatch,
+			Recommendations: []string{"github.com/foo/baz"},
+			Severity:        gomodguard.SeverityWarning,
+		},
+		{
+			FileName:   "second.go",
+			LineNumber: 20,
+			Reason:     "second test reason",
+		},
+	}
+
+	err := cli.WriteJSON(outFile, issues)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	want := `{
+  "issues": [
+    {
+      "file": "first.go",
+      "line": 10,
+      "column": 1,
+      "kind": "blocked",
+      "severity": "warning",
+      "package": "github.com/foo/bar/baz",
+      "module": "github.com/foo/bar",
+      "version": "v1.0.0",
+      "rule": "github.com/foo",
+      "rule_id": "blocked/github.com/foo",
+      "match_type": "prefix",
+      "recommendations": [
+        "github.com/foo/baz"
+      ],
+      "reason": "first test reason"
+    },
+    {
+      "file": "second.go",
+      "line": 20,
+      "column": 1,
+      "severity": "error",
+      "reason": "second test reason"
+    }
+  ]
+}
+`
+	assert.Equal(t, want, string(got))
+}
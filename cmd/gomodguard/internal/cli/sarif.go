@@ -0,0 +1,431 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/ryancurrah/gomodguard"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// WriteSARIF takes the results and writes them to a SARIF 2.1.0 formatted file.
+// Each blocked module, allowed module with a version constraint, the allowed
+// list and the replace rules of the configuration is described as a rule of
+// its own.
+func WriteSARIF(sarifFilePath string, config *gomodguard.Configuration, results []gomodguard.Issue) error {
+	rules, ruleIndexes := sarifRules(config)
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "gomodguard",
+				Version:        toolVersion(),
+				InformationURI: sarifToolURI,
+				Rules:          rules,
+			},
+		},
+		Results: make([]sarifResult, 0, len(results)),
+	}
+
+	for i := range results {
+		result := sarifResult{
+			RuleID:  results[i].RuleID,
+			Level:   sarifLevel(results[i].EffectiveSeverity()),
+			Message: sarifMessage{Text: results[i].Reason},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(results[i].FileName)},
+					},
+				},
+			},
+		}
+
+		if idx, ok := ruleIndexes[result.RuleID]; ok {
+			result.RuleIndex = &idx
+		}
+
+		if results[i].LineNumber > 0 {
+			column := results[i].Position.Column
+			if column == 0 {
+				column = 1
+			}
+
+			result.Locations[0].PhysicalLocation.Region = &sarifRegion{
+				StartLine:   results[i].LineNumber,
+				StartColumn: column,
+			}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	body, err := json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(sarifFilePath, append(body, '\n'), 0644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sarifRules returns the rules of the configuration and the index of each rule by its id.
+func sarifRules(config *gomodguard.Configuration) ([]sarifRule, map[string]int) {
+	rules := []sarifRule{}
+
+	if config != nil {
+		rules = append(rules, sarifModuleRules(config.Blocked, config.Allowed, config.NotAllowedSeverity)...)
+
+		for _, pattern := range slices.Sorted(maps.Keys(config.Overrides)) {
+			override := config.Overrides[pattern]
+			rules = append(rules, sarifModuleRules(override.Blocked, override.Allowed, config.NotAllowedSeverity)...)
+		}
+
+		if len(config.Tools) > 0 {
+			paths := make([]string, 0, len(config.Tools))
+			for i := range config.Tools {
+				paths = append(paths, fmt.Sprintf("`%s`", config.Tools[i].Path))
+			}
+
+			rules = append(rules, sarifRule{
+				ID:               gomodguard.NotAllowedToolRuleID,
+				ShortDescription: sarifMessage{Text: "Tool is not in the allowed tools list."},
+				Help: sarifMessage{
+					Text: fmt.Sprintf("Only tools in the allowed tools list may be used: %s.", strings.Join(paths, ", ")),
+				},
+				DefaultConfiguration: sarifConfiguration{Level: "error"},
+			})
+		}
+
+		if config.Transitive {
+			rules = append(rules, sarifRule{
+				ID:               gomodguard.IncompleteModuleGraphRuleID,
+				ShortDescription: sarifMessage{Text: "Transitive dependencies cannot be fully checked."},
+				Help: sarifMessage{
+					Text: "Run `go mod download` so the go.mod file of every module in the module graph is in the module cache.",
+				},
+				DefaultConfiguration: sarifConfiguration{Level: "warning"},
+			})
+		}
+
+		rules = append(rules, sarifReplaceRules(config.Replace)...)
+
+		if config.GoVersion.Go != nil {
+			rules = append(rules, sarifRule{
+				ID:               gomodguard.GoDirectiveRuleID,
+				ShortDescription: sarifMessage{Text: "The go directive does not meet the go version constraint."},
+				Help: sarifMessage{
+					Text: fmt.Sprintf("The go directive must meet the constraint `%s`.", config.GoVersion.Go),
+				},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(config.GoVersion.Severity)},
+			})
+		}
+
+		if config.GoVersion.Toolchain != nil {
+			rules = append(rules, sarifRule{
+				ID:               gomodguard.ToolchainDirectiveRuleID,
+				ShortDescription: sarifMessage{Text: "The toolchain does not meet the toolchain version constraint."},
+				Help: sarifMessage{
+					Text: fmt.Sprintf("The toolchain directive, or the go directive without it, must meet the constraint `%s`.",
+						config.GoVersion.Toolchain),
+				},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(config.GoVersion.Severity)},
+			})
+		}
+	}
+
+	rules = append(rules,
+		sarifRule{
+			ID:                   gomodguard.UnusedSuppressionRuleID,
+			ShortDescription:     sarifMessage{Text: "Suppression comment does not suppress any issue."},
+			Help:                 sarifMessage{Text: "Remove `//gomodguard:ignore` comments that no longer suppress an issue."},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		},
+		sarifRule{
+			ID:               gomodguard.SuppressionWithoutReasonRuleID,
+			ShortDescription: sarifMessage{Text: "Suppression comment does not give a reason."},
+			Help: sarifMessage{
+				Text: "Suppression comments must explain why the issue is acknowledged, e.g. `//gomodguard:ignore [rule-id] reason`.",
+			},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		},
+		sarifRule{
+			ID:               gomodguard.UnknownSuppressionRuleID,
+			ShortDescription: sarifMessage{Text: "Suppression comment names unknown rule ids."},
+			Help: sarifMessage{
+				Text: "Suppression comments must name the ids of configured or built-in rules, no issue is suppressed for unknown ids.",
+			},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		},
+	)
+
+	// An override rule with the ID of a top-level rule, or of a rule of
+	// another override, is described by the first of them.
+	ruleIndexes := make(map[string]int, len(rules))
+	unique := rules[:0]
+
+	for _, rule := range rules {
+		if _, ok := ruleIndexes[rule.ID]; ok {
+			continue
+		}
+
+		ruleIndexes[rule.ID] = len(unique)
+		unique = append(unique, rule)
+	}
+
+	return unique, ruleIndexes
+}
+
+// sarifModuleRules returns the rules of a blocked and an allowed list: every
+// blocked rule, the allowed rules that report issues and the allowed list.
+func sarifModuleRules(blocked gomodguard.Blocked, allowed gomodguard.Allowed, notAllowedSeverity gomodguard.Severity) []sarifRule {
+	var rules []sarifRule
+
+	for i := range blocked {
+		rules = append(rules, sarifRule{
+			ID:                   blocked[i].RuleID(),
+			ShortDescription:     sarifMessage{Text: fmt.Sprintf("Module `%s` is blocked.", blocked[i].Module)},
+			Help:                 sarifMessage{Text: blockedRuleHelp(&blocked[i])},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(blocked[i].Severity)},
+		})
+	}
+
+	for i := range allowed {
+		if allowed[i].Version == nil && allowed[i].Expires == nil {
+			continue
+		}
+
+		rules = append(rules, sarifAllowedRule(&allowed[i]))
+	}
+
+	if len(allowed) > 0 {
+		rules = append(rules, sarifRule{
+			ID:                   gomodguard.NotAllowedRuleID,
+			ShortDescription:     sarifMessage{Text: "Module is not in the allowed modules list."},
+			Help:                 sarifMessage{Text: allowedRuleHelp(allowed)},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(notAllowedSeverity)},
+		})
+	}
+
+	return rules
+}
+
+// sarifReplaceRules returns the blocked replace rules of each kind of replace
+// directive, and the allowed replaces list of each kind that has one.
+func sarifReplaceRules(policy gomodguard.ReplacePolicy) []sarifRule {
+	var rules []sarifRule
+
+	for _, kind := range []struct {
+		kind  gomodguard.ReplaceKind
+		rules gomodguard.ReplaceRules
+	}{
+		{gomodguard.ReplaceKindLocal, policy.Local},
+		{gomodguard.ReplaceKindFork, policy.Fork},
+		{gomodguard.ReplaceKindVersion, policy.Version},
+	} {
+		for i := range kind.rules.Blocked {
+			rule := &kind.rules.Blocked[i]
+
+			help := fmt.Sprintf("Replaces of module `%s` with `%s` are blocked.", cmp.Or(rule.Old, "*"), cmp.Or(rule.New, "*"))
+			if rule.Reason != "" {
+				help += fmt.Sprintf(" %s.", strings.TrimRight(rule.Reason, "."))
+			}
+
+			rules = append(rules, sarifRule{
+				ID:                   rule.RuleID(kind.kind, "blocked"),
+				ShortDescription:     sarifMessage{Text: fmt.Sprintf("The %s replace is blocked.", kind.kind)},
+				Help:                 sarifMessage{Text: help},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+			})
+		}
+
+		if len(kind.rules.Allowed) > 0 {
+			rules = append(rules, sarifRule{
+				ID:               gomodguard.NotAllowedReplaceRuleID(kind.kind),
+				ShortDescription: sarifMessage{Text: fmt.Sprintf("The %s replace is not in the allowed replaces list.", kind.kind)},
+				Help: sarifMessage{
+					Text: fmt.Sprintf("Only %s replaces in the allowed replaces list may be used.", kind.kind),
+				},
+				DefaultConfiguration: sarifConfiguration{Level: "error"},
+			})
+		}
+	}
+
+	return rules
+}
+
+// sarifAllowedRule returns the rule of an allowed module rule with a version
+// constraint or an expiry date, the only allowed rules that report issues.
+func sarifAllowedRule(rule *gomodguard.AllowedModule) sarifRule {
+	short := fmt.Sprintf("Module `%s` does not meet the allowed version constraint.", rule.Module)
+
+	var help []string
+
+	if rule.Version != nil {
+		help = append(help, fmt.Sprintf("Only versions of module `%s` meeting the constraint `%s` are allowed.",
+			rule.Module, rule.Version))
+	}
+
+	if rule.Expires != nil {
+		if rule.Version == nil {
+			short = fmt.Sprintf("The allowed rule for module `%s` expires.", rule.Module)
+		}
+
+		help = append(help, fmt.Sprintf("Module `%s` is allowed until %s.", rule.Module, rule.Expires))
+	}
+
+	return sarifRule{
+		ID:                   rule.RuleID(),
+		ShortDescription:     sarifMessage{Text: short},
+		Help:                 sarifMessage{Text: strings.Join(help, " ")},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+	}
+}
+
+// blockedRuleHelp describes a blocked module rule, its version constraint,
+// waiver, recommendations and reason.
+func blockedRuleHelp(rule *gomodguard.BlockedModule) string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "Module `%s` is in the blocked modules list.", rule.Module)
+
+	if rule.Version != nil {
+		_, _ = fmt.Fprintf(&sb, " Versions meeting the constraint `%s` are blocked.", rule.Version)
+	}
+
+	if rule.AllowUntil != nil {
+		_, _ = fmt.Fprintf(&sb, " The rule is waived until %s.", rule.AllowUntil)
+	}
+
+	if rule.HasRecommendations() {
+		_, _ = fmt.Fprintf(&sb, " Recommended modules: `%s`.", strings.Join(rule.Recommendations, "`, `"))
+	}
+
+	if rule.Reason != "" {
+		_, _ = fmt.Fprintf(&sb, " %s.", strings.TrimRight(rule.Reason, "."))
+	}
+
+	return sb.String()
+}
+
+// allowedRuleHelp describes the allowed modules list.
+func allowedRuleHelp(allowed gomodguard.Allowed) string {
+	modules := make([]string, 0, len(allowed))
+
+	for i := range allowed {
+		if allowed[i].Version != nil {
+			modules = append(modules, fmt.Sprintf("`%s` (%s)", allowed[i].Module, allowed[i].Version))
+			continue
+		}
+
+		modules = append(modules, fmt.Sprintf("`%s`", allowed[i].Module))
+	}
+
+	return fmt.Sprintf("Only modules in the allowed modules list may be used: %s.", strings.Join(modules, ", "))
+}
+
+// sarifLevel returns the SARIF level of a severity, an empty severity is an
+// error.
+func sarifLevel(severity gomodguard.Severity) string {
+	switch severity {
+	case gomodguard.SeverityWarning:
+		return "warning"
+	case gomodguard.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// toolVersion returns the version of the gomodguard binary, if known.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "(devel)" {
+		return ""
+	}
+
+	return info.Main.Version
+}
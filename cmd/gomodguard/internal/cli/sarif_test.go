@@ -0,0 +1,329 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestWriteSARIF(t *testing.T) { //nolint:funlen
+	outFile := t.TempDir() + "/report.sarif"
+
+	constraint, err := semver.NewConstraint(">= 1.2.0")
+	require.NoError(t, err)
+
+	config := &gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{ID: "allowed-foo", Module: "github.com/foo/allowed", Version: constraint},
+			{Module: "github.com/foo/unversioned"},
+		},
+		Blocked: gomodguard.Blocked{
+			{
+				Module:          "github.com/foo/blocked",
+				Recommendations: []string{"github.com/foo/recommended"},
+				Reason:          "blocked for testing.",
+			},
+		},
+		LocalReplaceDirectives: true,
+	}
+	require.NoError(t, config.InitMatchers())
+
+	issues := []gomodguard.Issue{
+		{
+			FileName:   "pkg/first.go",
+			LineNumber: 10,
+			Reason:     "first test reason",
+			Kind:       gomodguard.IssueKindBlocked,
+			Rule:       "github.com/foo/blocked",
+			RuleID:     "blocked/github.com/foo/blocked",
+		},
+		{
+			FileName:   "second.go",
+			LineNumber: 20,
+			Reason:     "second test reason",
+			Kind:       gomodguard.IssueKindNotAllowed,
+			RuleID:     gomodguard.NotAllowedRuleID,
+		},
+		{
+			FileName: "third.go",
+			Reason:   "third test reason",
+		},
+	}
+
+	err = cli.WriteSARIF(outFile, config, issues)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	var got struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID   string `json:"id"`
+						Help struct {
+							Text string `json:"text"`
+						} `json:"help"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex *int   `json:"ruleIndex"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, "2.1.0", got.Version)
+	require.Len(t, got.Runs, 1)
+
+	run := got.Runs[0]
+	assert.Equal(t, "gomodguard", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 8)
+	assert.Equal(t, "blocked/github.com/foo/blocked", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "Module `github.com/foo/blocked` is in the blocked modules list. "+
+		"Recommended modules: `github.com/foo/recommended`. blocked for testing.", run.Tool.Driver.Rules[0].Help.Text)
+	assert.Equal(t, "allowed-foo", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, "not-allowed", run.Tool.Driver.Rules[2].ID)
+	assert.Equal(t, "Only modules in the allowed modules list may be used: `github.com/foo/allowed` (>=1.2.0), "+
+		"`github.com/foo/unversioned`.", run.Tool.Driver.Rules[2].Help.Text)
+	assert.Equal(t, "local-replace-directives", run.Tool.Driver.Rules[3].ID)
+	assert.Equal(t, "replace/local/not-allowed", run.Tool.Driver.Rules[4].ID)
+	assert.Equal(t, "unused-suppression", run.Tool.Driver.Rules[5].ID)
+	assert.Equal(t, "suppression-without-reason", run.Tool.Driver.Rules[6].ID)
+	assert.Equal(t, "unknown-suppression-rule", run.Tool.Driver.Rules[7].ID)
+
+	require.Len(t, run.Results, 3)
+
+	assert.Equal(t, "blocked/github.com/foo/blocked", run.Results[0].RuleID)
+	require.NotNil(t, run.Results[0].RuleIndex)
+	assert.Equal(t, 0, *run.Results[0].RuleIndex)
+	assert.Equal(t, "first test reason", run.Results[0].Message.Text)
+	assert.Equal(t, "pkg/first.go", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.NotNil(t, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 10, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 1, run.Results[0].Locations[0].PhysicalLocation.Region.StartColumn)
+
+	assert.Equal(t, "not-allowed", run.Results[1].RuleID)
+	require.NotNil(t, run.Results[1].RuleIndex)
+	assert.Equal(t, 2, *run.Results[1].RuleIndex)
+
+	assert.Empty(t, run.Results[2].RuleID)
+	assert.Nil(t, run.Results[2].RuleIndex)
+	assert.Nil(t, run.Results[2].Locations[0].PhysicalLocation.Region)
+}
+
+func TestWriteSARIFExpiry(t *testing.T) {
+	outFile := t.TempDir() + "/report.sarif"
+
+	config := &gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{Module: "github.com/foo/allowed", Expires: gomodguard.NewDate(2026, time.March, 1)},
+			{Module: "github.com/foo/unversioned"},
+		},
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/foo/blocked", AllowUntil: gomodguard.NewDate(2026, time.April, 1)},
+		},
+	}
+
+	issues := []gomodguard.Issue{
+		{FileName: "go.mod", Kind: gomodguard.IssueKindExpired, RuleID: "allowed/github.com/foo/allowed"},
+		{
+			FileName: "go.mod",
+			Kind:     gomodguard.IssueKindExpiring,
+			RuleID:   "blocked/github.com/foo/blocked",
+			Severity: gomodguard.SeverityWarning,
+		},
+	}
+
+	require.NoError(t, cli.WriteSARIF(outFile, config, issues))
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	var got struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID   string `json:"id"`
+						Help struct {
+							Text string `json:"text"`
+						} `json:"help"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleIndex *int `json:"ruleIndex"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got.Runs, 1)
+
+	rules := got.Runs[0].Tool.Driver.Rules
+	require.GreaterOrEqual(t, len(rules), 2)
+	assert.Equal(t, "blocked/github.com/foo/blocked", rules[0].ID)
+	assert.Equal(t, "Module `github.com/foo/blocked` is in the blocked modules list. The rule is waived until 2026-04-01.",
+		rules[0].Help.Text)
+	assert.Equal(t, "allowed/github.com/foo/allowed", rules[1].ID)
+	assert.Equal(t, "Module `github.com/foo/allowed` is allowed until 2026-03-01.", rules[1].Help.Text)
+
+	require.Len(t, got.Runs[0].Results, 2)
+	require.NotNil(t, got.Runs[0].Results[0].RuleIndex)
+	assert.Equal(t, 1, *got.Runs[0].Results[0].RuleIndex)
+	require.NotNil(t, got.Runs[0].Results[1].RuleIndex)
+	assert.Equal(t, 0, *got.Runs[0].Results[1].RuleIndex)
+}
+
+func TestWriteSARIFOverrides(t *testing.T) {
+	outFile := t.TempDir() + "/report.sarif"
+
+	config := &gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{{Module: "github.com/foo/blocked"}},
+		Overrides: map[string]gomodguard.Override{
+			"cmd/*": {
+				Blocked: gomodguard.Blocked{
+					{Module: "github.com/foo/blocked", Reason: "blocked again."},
+					{Module: "github.com/foo/cmd"},
+				},
+			},
+			"internal/*": {Allowed: gomodguard.Allowed{{Module: "github.com/foo/internal"}}},
+		},
+	}
+
+	issues := []gomodguard.Issue{
+		{FileName: "cmd/a/main.go", RuleID: "blocked/github.com/foo/cmd"},
+		{FileName: "internal/a/a.go", RuleID: gomodguard.NotAllowedRuleID},
+	}
+
+	require.NoError(t, cli.WriteSARIF(outFile, config, issues))
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	var got struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID   string `json:"id"`
+						Help struct {
+							Text string `json:"text"`
+						} `json:"help"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleIndex *int `json:"ruleIndex"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got.Runs, 1)
+
+	ids := make([]string, 0, len(got.Runs[0].Tool.Driver.Rules))
+	for _, rule := range got.Runs[0].Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+
+	assert.Equal(t, []string{
+		"blocked/github.com/foo/blocked",
+		"blocked/github.com/foo/cmd",
+		gomodguard.NotAllowedRuleID,
+		gomodguard.UnusedSuppressionRuleID,
+		gomodguard.SuppressionWithoutReasonRuleID,
+		gomodguard.UnknownSuppressionRuleID,
+	}, ids)
+	assert.Equal(t, "Module `github.com/foo/blocked` is in the blocked modules list.",
+		got.Runs[0].Tool.Driver.Rules[0].Help.Text)
+
+	require.Len(t, got.Runs[0].Results, 2)
+	require.NotNil(t, got.Runs[0].Results[0].RuleIndex)
+	assert.Equal(t, 1, *got.Runs[0].Results[0].RuleIndex)
+	require.NotNil(t, got.Runs[0].Results[1].RuleIndex)
+	assert.Equal(t, 2, *got.Runs[0].Results[1].RuleIndex)
+}
+
+func TestWriteSARIFLevels(t *testing.T) {
+	outFile := t.TempDir() + "/report.sarif"
+
+	config := &gomodguard.Configuration{
+		Allowed:            gomodguard.Allowed{{Module: "github.com/foo/allowed"}},
+		Blocked:            gomodguard.Blocked{{Module: "github.com/foo/blocked", Severity: gomodguard.SeverityWarning}},
+		NotAllowedSeverity: gomodguard.SeverityInfo,
+	}
+
+	issues := []gomodguard.Issue{
+		{FileName: "first.go", RuleID: "blocked/github.com/foo/blocked", Severity: gomodguard.SeverityWarning},
+		{FileName: "second.go", RuleID: gomodguard.NotAllowedRuleID, Severity: gomodguard.SeverityInfo},
+		{FileName: "third.go"},
+	}
+
+	require.NoError(t, cli.WriteSARIF(outFile, config, issues))
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	var got struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				Level string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got.Runs, 1)
+
+	levels := make(map[string]string)
+	for _, rule := range got.Runs[0].Tool.Driver.Rules {
+		levels[rule.ID] = rule.DefaultConfiguration.Level
+	}
+
+	assert.Equal(t, "warning", levels["blocked/github.com/foo/blocked"])
+	assert.Equal(t, "note", levels[gomodguard.NotAllowedRuleID])
+
+	resultLevels := make([]string, 0, len(got.Runs[0].Results))
+	for _, result := range got.Runs[0].Results {
+		resultLevels = append(resultLevels, result.Level)
+	}
+
+	assert.Equal(t, []string{"warning", "note", "error"}, resultLevels)
+}
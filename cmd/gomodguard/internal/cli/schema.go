@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// PrintSchema prints the JSON Schema of the config file. Returns the exit code to use.
+func PrintSchema() int {
+	schema, err := gomodguard.JSONSchema()
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	if _, err := os.Stdout.Write(schema); err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	return 0
+}
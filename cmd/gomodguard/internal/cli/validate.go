@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+// Validate strictly checks the config file given in args, or the one found
+// like for linting, and prints its errors and warnings. Returns the exit code
+// to use, which is 1 when the config file has errors.
+func Validate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	policyDir := flags.String("policy-dir", "", "Directory to look up extended config files in when they are not "+
+		"found relative to the extending file, overrides the "+gomodguard.PolicyDirEnvVar+" environment variable")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	cwd, _ := os.Getwd()
+
+	cfgFile, err := FindConfigFile(flags.Arg(0), cwd)
+	if err != nil {
+		logger.Printf("error: %s", err)
+		return 1
+	}
+
+	exitCode := 0
+
+	problems := gomodguard.ValidateConfiguration(cfgFile, *policyDir)
+	for _, problem := range problems {
+		fmt.Println(problem.String())
+
+		if problem.Level == gomodguard.ProblemError {
+			exitCode = 1
+		}
+	}
+
+	if len(problems) == 0 {
+		logger.Printf("info: config file %s is valid", cfgFile)
+	}
+
+	return exitCode
+}
@@ -0,0 +1,38 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/cmd/gomodguard/v2/internal/cli"
+)
+
+func TestValidateExamples(t *testing.T) {
+	configFiles, err := filepath.Glob(filepath.Join(examplesDir, "*", ".gomodguard.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, configFiles)
+
+	for _, configFile := range configFiles {
+		name := filepath.Base(filepath.Dir(configFile))
+
+		t.Run(name, func(t *testing.T) {
+			want := 0
+			if name == "invalidconstraint" {
+				want = 1
+			}
+
+			assert.Equal(t, want, cli.Validate([]string{configFile}))
+		})
+	}
+}
+
+func TestValidateInvalidConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".gomodguard.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("blocked:\n  - module: foo\n    recomendations: [bar]\n"), 0o600))
+
+	assert.Equal(t, 1, cli.Validate([]string{configFile}))
+}
@@ -0,0 +1,230 @@
+package gomodguard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go.yaml.in/yaml/v4"
+)
+
+const (
+	// PolicyDirEnvVar is the environment variable holding the directory that
+	// extended config files are looked up in when they are not found relative
+	// to the extending file.
+	PolicyDirEnvVar = "GOMODGUARD_POLICY_DIR"
+
+	errReadingConfigFile = "could not read config file: %w"
+	errParsingConfigFile = "could not parse config file: %w"
+)
+
+var errExtendedConfigFileNotFound = errors.New("could not find extended config file")
+
+// RemovedRules lists the modules of inherited allowed and blocked rules that
+// are dropped from a configuration.
+type RemovedRules struct {
+	Allowed []string `yaml:"allowed,omitempty"`
+	Blocked []string `yaml:"blocked,omitempty"`
+}
+
+// configFlags records which boolean options and numeric options a config file
+// sets, so that inherited values are only overridden when set explicitly, even
+// to false or zero.
+type configFlags struct {
+	LocalReplaceDirectives *bool `yaml:"local_replace_directives"`
+	Transitive             *bool `yaml:"transitive"`
+	ExpiryWarningDays      *int  `yaml:"expiry_warning_days"`
+}
+
+// LoadConfiguration reads the config file at path and merges in the config
+// files it extends.
+//
+// Extended files are merged in order, so a later file overrides an earlier
+// one, and the extending file overrides them all. A rule overrides an inherited
+// rule for the same module in the same list and other rules are appended, a
+// replace rule overrides an inherited replace rule with the same ID.
+// Inherited rules listed under remove are dropped. Boolean options, the not
+// allowed severity, the expiry warning window and the fields of the go version
+// policy are inherited unless the extending file sets them, and an override
+// replaces the inherited override of the same path glob.
+//
+// Relative paths in extends are resolved against the directory of the
+// extending file, or else against policyDir. When policyDir is empty the
+// GOMODGUARD_POLICY_DIR environment variable is used.
+func LoadConfiguration(path, policyDir string) (*Configuration, error) {
+	if policyDir == "" {
+		policyDir = os.Getenv(PolicyDirEnvVar)
+	}
+
+	config, _, err := loadConfiguration(path, policyDir, nil)
+
+	return config, err
+}
+
+// loadConfiguration returns the merged configuration of the config file at
+// path and the options set by it or by the files it extends.
+func loadConfiguration(path, policyDir string, extendedBy []string) (*Configuration, configFlags, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, configFlags{}, fmt.Errorf(errReadingConfigFile, err)
+	}
+
+	if slices.Contains(extendedBy, absPath) {
+		return nil, configFlags{}, fmt.Errorf("config file %s extends itself", path)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, configFlags{}, fmt.Errorf(errReadingConfigFile, err)
+	}
+
+	var (
+		config Configuration
+		flags  configFlags
+	)
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, configFlags{}, fmt.Errorf(errParsingConfigFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return nil, configFlags{}, fmt.Errorf(errParsingConfigFile, err)
+	}
+
+	if len(config.Extends) == 0 {
+		return &config, flags, nil
+	}
+
+	var (
+		inherited      = &Configuration{Overrides: make(map[string]Override)}
+		inheritedFlags configFlags
+	)
+
+	for _, extends := range config.Extends {
+		extendsPath, err := resolveExtends(extends, filepath.Dir(path), policyDir)
+		if err != nil {
+			return nil, configFlags{}, fmt.Errorf("config file %s: %w", path, err)
+		}
+
+		parent, parentFlags, err := loadConfiguration(extendsPath, policyDir, append(extendedBy, absPath))
+		if err != nil {
+			return nil, configFlags{}, err
+		}
+
+		inherited.Allowed = mergeRules(inherited.Allowed, parent.Allowed, allowedRuleModule)
+		inherited.Blocked = mergeRules(inherited.Blocked, parent.Blocked, blockedRuleModule)
+
+		if parentFlags.LocalReplaceDirectives != nil {
+			inherited.LocalReplaceDirectives = parent.LocalReplaceDirectives
+			inheritedFlags.LocalReplaceDirectives = parentFlags.LocalReplaceDirectives
+		}
+
+		if parentFlags.Transitive != nil {
+			inherited.Transitive = parent.Transitive
+			inheritedFlags.Transitive = parentFlags.Transitive
+		}
+
+		if parent.NotAllowedSeverity != "" {
+			inherited.NotAllowedSeverity = parent.NotAllowedSeverity
+		}
+
+		if parentFlags.ExpiryWarningDays != nil {
+			inherited.ExpiryWarningDays = parent.ExpiryWarningDays
+			inheritedFlags.ExpiryWarningDays = parentFlags.ExpiryWarningDays
+		}
+
+		inherited.Replace = inherited.Replace.merge(parent.Replace)
+		inherited.GoVersion = parent.GoVersion.withParent(inherited.GoVersion)
+		inherited.Tools = mergeRules(inherited.Tools, parent.Tools, allowedToolPath)
+		maps.Copy(inherited.Overrides, parent.Overrides)
+	}
+
+	merged := inherited.withOverride(Override{
+		Allowed: config.Allowed,
+		Blocked: config.Blocked,
+		Remove:  config.Remove,
+	})
+	merged.Overrides = inherited.Overrides
+	merged.Replace = inherited.Replace.merge(config.Replace)
+	merged.GoVersion = config.GoVersion.withParent(inherited.GoVersion)
+	merged.Tools = mergeRules(inherited.Tools, config.Tools, allowedToolPath)
+	maps.Copy(merged.Overrides, config.Overrides)
+
+	if flags.LocalReplaceDirectives != nil {
+		merged.LocalReplaceDirectives = *flags.LocalReplaceDirectives
+	} else {
+		flags.LocalReplaceDirectives = inheritedFlags.LocalReplaceDirectives
+	}
+
+	if flags.Transitive != nil {
+		merged.Transitive = *flags.Transitive
+	} else {
+		flags.Transitive = inheritedFlags.Transitive
+	}
+
+	if config.NotAllowedSeverity != "" {
+		merged.NotAllowedSeverity = config.NotAllowedSeverity
+	}
+
+	if flags.ExpiryWarningDays != nil {
+		merged.ExpiryWarningDays = *flags.ExpiryWarningDays
+	} else {
+		flags.ExpiryWarningDays = inheritedFlags.ExpiryWarningDays
+	}
+
+	return merged, flags, nil
+}
+
+// resolveExtends returns the path of an extended config file.
+func resolveExtends(extends, dir, policyDir string) (string, error) {
+	if filepath.IsAbs(extends) {
+		return extends, nil
+	}
+
+	candidates := []string{filepath.Join(dir, extends)}
+	if policyDir != "" {
+		candidates = append(candidates, filepath.Join(policyDir, extends))
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errExtendedConfigFileNotFound, extends)
+}
+
+func allowedRuleModule(r AllowedModule) string { return r.Module }
+
+func blockedRuleModule(r BlockedModule) string { return r.Module }
+
+func allowedToolPath(t AllowedTool) string { return t.Path }
+
+// removeRules returns the rules whose module is not in modules.
+func removeRules[T any](rules []T, modules []string, module func(T) string) []T {
+	return slices.DeleteFunc(slices.Clone(rules), func(r T) bool {
+		return slices.Contains(modules, module(r))
+	})
+}
+
+// mergeRules returns the inherited rules with each rule of the same module
+// replaced by the overriding rule, followed by the remaining overriding rules.
+func mergeRules[T any](inherited, overrides []T, module func(T) string) []T {
+	merged := slices.Clone(inherited)
+
+	for _, override := range overrides {
+		i := slices.IndexFunc(merged, func(r T) bool { return module(r) == module(override) })
+		if i >= 0 {
+			merged[i] = override
+			continue
+		}
+
+		merged = append(merged, override)
+	}
+
+	return merged
+}
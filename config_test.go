@@ -0,0 +1,130 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestLoadConfigurationExtends(t *testing.T) {
+	config, err := gomodguard.LoadConfiguration("examples/extends/.gomodguard.yaml", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, gomodguard.Allowed{
+		{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+		{Module: "github.com/mitchellh/go-homedir"},
+	}, config.Allowed)
+	assert.Equal(t, gomodguard.Blocked{
+		{
+			Module: "github.com/uudashr/go-module",
+			Reason: "testing if the team rule overrides the organisation rule.",
+		},
+		{Module: "github.com/gofrs/uuid", Reason: "use github.com/google/uuid instead."},
+	}, config.Blocked)
+	assert.False(t, config.LocalReplaceDirectives)
+	assert.Equal(t, gomodguard.SeverityWarning, config.NotAllowedSeverity)
+	assert.Empty(t, config.Extends)
+}
+
+func TestLoadConfigurationExtendsBooleanOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "a.yaml"):        "local_replace_directives: true\ntransitive: true\n",
+		filepath.Join(dir, "b.yaml"):        "blocked:\n  - module: example.com/b\n",
+		filepath.Join(dir, "parent.yaml"):   "extends:\n  - a.yaml\n",
+		filepath.Join(dir, "both.yaml"):     "extends:\n  - a.yaml\n  - b.yaml\n",
+		filepath.Join(dir, "nested.yaml"):   "extends:\n  - parent.yaml\n  - b.yaml\n",
+		filepath.Join(dir, "disabled.yaml"): "extends:\n  - a.yaml\n  - b.yaml\ntransitive: false\n",
+	})
+
+	tests := map[string]struct {
+		path                       string
+		wantLocalReplaceDirectives bool
+		wantTransitive             bool
+	}{
+		"later parent does not set the options": {path: "both.yaml", wantLocalReplaceDirectives: true, wantTransitive: true},
+		"options set by a grandparent":          {path: "nested.yaml", wantLocalReplaceDirectives: true, wantTransitive: true},
+		"extending file sets an option":         {path: "disabled.yaml", wantLocalReplaceDirectives: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := gomodguard.LoadConfiguration(filepath.Join(dir, tt.path), "")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantLocalReplaceDirectives, config.LocalReplaceDirectives)
+			assert.Equal(t, tt.wantTransitive, config.Transitive)
+		})
+	}
+}
+
+func TestLoadConfigurationExtendsResolution(t *testing.T) {
+	tmpDir := t.TempDir()
+	policyDir := filepath.Join(tmpDir, "policy")
+	require.NoError(t, os.MkdirAll(policyDir, 0o755))
+
+	writeFile := func(path, data string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	}
+
+	writeFile(filepath.Join(policyDir, "org.yaml"), "blocked:\n  - module: example.com/org\ntransitive: true\n")
+	writeFile(filepath.Join(tmpDir, "team.yaml"), "extends:\n  - org.yaml\nblocked:\n  - module: example.com/team\n")
+	writeFile(filepath.Join(tmpDir, "cycle.yaml"), "extends:\n  - cycle.yaml\n")
+	writeFile(filepath.Join(tmpDir, "missing.yaml"), "extends:\n  - missing-policy.yaml\n")
+
+	tests := map[string]struct {
+		path      string
+		policyDir string
+		envDir    string
+		want      []string
+		wantErr   string
+	}{
+		"extended file from the policy directory": {
+			path:      "team.yaml",
+			policyDir: policyDir,
+			want:      []string{"example.com/org", "example.com/team"},
+		},
+		"policy directory from the environment": {
+			path:   "team.yaml",
+			envDir: policyDir,
+			want:   []string{"example.com/org", "example.com/team"},
+		},
+		"extended file not found": {
+			path:    "missing.yaml",
+			wantErr: "could not find extended config file: missing-policy.yaml",
+		},
+		"config file extends itself": {
+			path:    "cycle.yaml",
+			wantErr: "extends itself",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(gomodguard.PolicyDirEnvVar, tt.envDir)
+
+			config, err := gomodguard.LoadConfiguration(filepath.Join(tmpDir, tt.path), tt.policyDir)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(config.Blocked))
+			for _, r := range config.Blocked {
+				got = append(got, r.Module)
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.True(t, config.Transitive)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func main() {
+	fmt.Println(homedir.Dir())
+}
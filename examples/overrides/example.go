@@ -0,0 +1,15 @@
+package overrides
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/mitchellh/go-homedir"
+)
+
+func NewID() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return home + uuid.Must(uuid.NewV4()).String(), nil
+}
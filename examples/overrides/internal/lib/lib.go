@@ -0,0 +1,7 @@
+package lib
+
+import "github.com/gofrs/uuid"
+
+func NewID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
@@ -0,0 +1,32 @@
+package suppression
+
+import (
+	"os"
+
+	//gomodguard:ignore [blocked/github.com/gofrs/uuid] migrating to the standard library.
+	"github.com/gofrs/uuid"
+	"github.com/mitchellh/go-homedir"     //gomodguard:ignore
+	module "github.com/uudashr/go-module" //gomodguard:ignore [not-allowed] wrong rule.
+	//gomodguard:ignore nothing to suppress.
+	"golang.org/x/mod/modfile"
+)
+
+func aSuppressedImport() { //nolint: deadcode,unused
+	b, err := os.ReadFile("go.mod")
+	if err != nil {
+		panic(err)
+	}
+
+	mod, err := module.Parse(b)
+	if err != nil {
+		panic(err)
+	}
+
+	_ = mod
+
+	_ = uuid.Must(uuid.NewV4())
+
+	_, _ = homedir.Expand("~/something")
+
+	_ = modfile.Format
+}
@@ -0,0 +1,10 @@
+module github.com/ryancurrah/gomodguard/examples/suppression
+
+go 1.25.0
+
+require (
+	github.com/gofrs/uuid v3.3.0+incompatible
+	github.com/mitchellh/go-homedir v1.1.0
+	github.com/uudashr/go-module v0.0.0-20200529023307-c90a4239ad70
+	golang.org/x/mod v0.34.0
+)
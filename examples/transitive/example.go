@@ -0,0 +1,5 @@
+package transitive
+
+import "example.com/direct"
+
+var _ = direct.Direct
@@ -0,0 +1,11 @@
+module github.com/ryancurrah/gomodguard/examples/transitive
+
+go 1.25.0
+
+require example.com/direct v1.0.0
+
+require (
+	example.com/blocked v1.3.0 // indirect
+	example.com/middle v1.0.0 // indirect
+	example.com/other v1.0.0 // indirect
+)
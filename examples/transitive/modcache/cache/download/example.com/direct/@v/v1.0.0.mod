@@ -0,0 +1,8 @@
+module example.com/direct
+
+go 1.25.0
+
+require (
+	example.com/middle v1.0.0
+	example.com/other v1.0.0
+)
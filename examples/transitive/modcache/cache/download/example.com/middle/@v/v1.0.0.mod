@@ -0,0 +1,8 @@
+module example.com/middle
+
+go 1.25.0
+
+require (
+	example.com/blocked v1.2.0
+	example.com/missing v1.0.0
+)
@@ -0,0 +1,7 @@
+module example.com/other
+
+go 1.25.0
+
+require (
+	example.com/blocked v1.3.0
+)
@@ -0,0 +1,7 @@
+package bar
+
+import "github.com/mitchellh/go-homedir"
+
+func Home() (string, error) {
+	return homedir.Dir()
+}
@@ -0,0 +1,5 @@
+module github.com/ryancurrah/gomodguard/examples/workspace/bar
+
+go 1.25.0
+
+require github.com/mitchellh/go-homedir v1.1.0
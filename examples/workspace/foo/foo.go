@@ -0,0 +1,7 @@
+package foo
+
+import "github.com/mitchellh/go-homedir"
+
+func Home() (string, error) {
+	return homedir.Dir()
+}
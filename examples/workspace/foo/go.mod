@@ -0,0 +1,5 @@
+module github.com/ryancurrah/gomodguard/examples/workspace/foo
+
+go 1.25.0
+
+require github.com/mitchellh/go-homedir v1.0.0
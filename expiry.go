@@ -0,0 +1,124 @@
+package gomodguard
+
+import (
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date, written as YYYY-MM-DD in config files. A date
+// lasts until the end of the day in UTC.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of the given day.
+func NewDate(year int, month time.Month, day int) *Date {
+	return &Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// UnmarshalText parses a YYYY-MM-DD date.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(time.DateOnly, string(text))
+	if err != nil {
+		return fmt.Errorf("invalid date `%s`, must be YYYY-MM-DD", text)
+	}
+
+	d.Time = t
+
+	return nil
+}
+
+// MarshalText formats the date as YYYY-MM-DD.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// String formats the date as YYYY-MM-DD.
+func (d Date) String() string {
+	return d.Format(time.DateOnly)
+}
+
+// passed returns true if the date is set and its day is over at now.
+func (d *Date) passed(now time.Time) bool {
+	return d != nil && !now.Before(d.AddDate(0, 0, 1))
+}
+
+// passesWithin returns true if the date is set and its day is not over at
+// now but will be within the given number of days.
+func (d *Date) passesWithin(now time.Time, days int) bool {
+	return d != nil && days > 0 && !d.passed(now) && d.passed(now.AddDate(0, 0, days))
+}
+
+// validateExpiryWarningDays returns an error when the expiry warning window
+// is negative.
+func (c *Configuration) validateExpiryWarningDays() error {
+	if c.ExpiryWarningDays < 0 {
+		return fmt.Errorf("invalid expiry_warning_days '%d', must not be negative", c.ExpiryWarningDays)
+	}
+
+	return nil
+}
+
+// now returns the current time, from Now when it is set.
+func (c *Configuration) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+
+	return time.Now()
+}
+
+// processExpiries returns a warning at the require line of every direct
+// requirement whose allowed rule expires, or whose blocked rule waiver ends,
+// within the expiry warning window.
+func (p *Processor) processExpiries() (issues []Issue) {
+	now := p.Config.now()
+
+	for _, r := range p.Modfile.Require {
+		if r.Indirect {
+			continue
+		}
+
+		if key, ok := p.blockedIdx.bestMatch(r.Mod.Path); ok {
+			rule := p.blockedLookup[key]
+			blocked, _ := rule.CheckVersion(r.Mod.Version)
+
+			if blocked && rule.AllowUntil.passesWithin(now, p.Config.ExpiryWarningDays) &&
+				!rule.IsCurrentModuleARecommendation(p.Modfile.Module.Mod.Path) {
+				issues = append(issues, p.addModFileError(r.Syntax,
+					fmt.Sprintf("the waiver of blocked rule `%s` for module `%s` ends on %s, the module is blocked after that date",
+						rule.RuleID(), r.Mod.Path, rule.AllowUntil),
+					expiringModule(key, r.Mod.Path, r.Mod.Version, rule.RuleID(), rule.MatchType),
+				))
+			}
+		}
+
+		if key, ok := p.allowedIdx.bestMatch(r.Mod.Path); ok {
+			rule := p.allowedLookup[key]
+
+			if rule.Expires.passesWithin(now, p.Config.ExpiryWarningDays) {
+				issues = append(issues, p.addModFileError(r.Syntax,
+					fmt.Sprintf("allowed rule `%s` for module `%s` expires on %s, the module is not allowed after that date",
+						rule.RuleID(), r.Mod.Path, rule.Expires),
+					expiringModule(key, r.Mod.Path, r.Mod.Version, rule.RuleID(), rule.MatchType),
+				))
+			}
+		}
+	}
+
+	return issues
+}
+
+// expiringModule returns the warning level blocked module of a rule about to
+// expire.
+func expiringModule(key, moduleName, moduleVersion, ruleID string, matchType MatchType) blockedModule {
+	return blockedModule{
+		kind:      IssueKindExpiring,
+		module:    moduleName,
+		version:   moduleVersion,
+		rule:      key,
+		ruleID:    ruleID,
+		matchType: matchType.orDefault(),
+		severity:  SeverityWarning,
+	}
+}
@@ -0,0 +1,146 @@
+package gomodguard_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorExpiry(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	tests := map[string]struct {
+		now  time.Time
+		want []string
+	}{
+		"before the warning window": {
+			now:  time.Date(2026, time.September, 1, 12, 0, 0, 0, time.UTC),
+			want: []string{},
+		},
+		"within the warning window": {
+			now: time.Date(2026, time.September, 20, 12, 0, 0, 0, time.UTC),
+			want: []string{
+				"go.mod:6 warning expiring blocked/github.com/gofrs/uuid",
+				"go.mod:7 warning expiring allowed/github.com/mitchellh/go-homedir",
+			},
+		},
+		"on the last day": {
+			now: time.Date(2026, time.September, 30, 23, 59, 0, 0, time.UTC),
+			want: []string{
+				"go.mod:6 warning expiring blocked/github.com/gofrs/uuid",
+				"go.mod:7 warning expiring allowed/github.com/mitchellh/go-homedir",
+			},
+		},
+		"after the last day": {
+			now: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+			want: []string{
+				"go.mod:6 error blocked blocked/github.com/gofrs/uuid",
+				"go.mod:7 error expired allowed/github.com/mitchellh/go-homedir",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+				Allowed: gomodguard.Allowed{
+					{Module: "github.com/mitchellh/go-homedir", Expires: gomodguard.NewDate(2026, time.September, 30)},
+					{Module: "github.com/gofrs/uuid"},
+					{Module: "github.com/uudashr/go-module"},
+					{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+				},
+				Blocked: gomodguard.Blocked{
+					{Module: "github.com/gofrs/uuid", AllowUntil: gomodguard.NewDate(2026, time.September, 30)},
+				},
+				ExpiryWarningDays: 14,
+				Now:               func() time.Time { return tt.now },
+			})
+			require.NoError(t, err)
+
+			issues := append(processor.ProcessRequires(), processor.ProcessModFile()...)
+
+			got := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, fmt.Sprintf("%s:%d %s %s %s", issue.FileName, issue.LineNumber,
+					issue.Severity, issue.Kind, issue.RuleID))
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcessorExpiryReasons(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{Module: "github.com/mitchellh/go-homedir", Expires: gomodguard.NewDate(2026, time.September, 30)},
+		},
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/gofrs/uuid", AllowUntil: gomodguard.NewDate(2026, time.October, 2)},
+		},
+		ExpiryWarningDays: 7,
+		Now:               func() time.Time { return time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC) },
+	})
+	require.NoError(t, err)
+
+	reasons := []string{}
+	for _, issue := range append(processor.ProcessRequires(), processor.ProcessModFile()...) {
+		if issue.Module == "github.com/mitchellh/go-homedir" || issue.Module == "github.com/gofrs/uuid" {
+			reasons = append(reasons, issue.Reason)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{
+		"requirement of module `github.com/mitchellh/go-homedir` is blocked because the allowed rule for the module " +
+			"expired on 2026-09-30.",
+		"requirement of module `github.com/gofrs/uuid` is blocked because the module is not in the allowed modules list.",
+		"the waiver of blocked rule `blocked/github.com/gofrs/uuid` for module `github.com/gofrs/uuid` ends on " +
+			"2026-10-02, the module is blocked after that date",
+	}, reasons)
+}
+
+func TestLoadConfigurationExtendsExpiryWarningDays(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "org.yaml"):      "expiry_warning_days: 14\n",
+		filepath.Join(dir, "inherits.yaml"): "extends:\n  - org.yaml\n",
+		filepath.Join(dir, "disables.yaml"): "extends:\n  - org.yaml\nexpiry_warning_days: 0\n",
+	})
+
+	config, err := gomodguard.LoadConfiguration(filepath.Join(dir, "inherits.yaml"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 14, config.ExpiryWarningDays)
+
+	config, err = gomodguard.LoadConfiguration(filepath.Join(dir, "disables.yaml"), "")
+	require.NoError(t, err)
+	assert.Zero(t, config.ExpiryWarningDays)
+}
+
+func TestProcessorNewProcessorNegativeExpiryWarningDays(t *testing.T) {
+	_, err := gomodguard.NewProcessor(&gomodguard.Configuration{ExpiryWarningDays: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid expiry_warning_days '-1', must not be negative")
+}
+
+func TestDateUnmarshal(t *testing.T) {
+	var rule gomodguard.AllowedModule
+
+	require.NoError(t, yaml.Unmarshal([]byte("module: foo\nexpires: 2026-09-30\n"), &rule))
+	assert.Equal(t, "2026-09-30", rule.Expires.String())
+
+	require.NoError(t, yaml.Unmarshal([]byte("module: foo\nexpires: \"2026-10-01\"\n"), &rule))
+	assert.Equal(t, "2026-10-01", rule.Expires.String())
+
+	require.ErrorContains(t, yaml.Unmarshal([]byte("module: foo\nexpires: next quarter\n"), &rule),
+		"invalid date `next quarter`, must be YYYY-MM-DD")
+}
@@ -0,0 +1,194 @@
+package gomodguard
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Explanation describes how the allowed and blocked rules apply to a module.
+type Explanation struct {
+	Module  string
+	Version string
+	// VersionFromModFile is true when the version is the one required in
+	// go.mod because none was given.
+	VersionFromModFile bool
+	// CurrentModule is the module of the go.mod file, used for the
+	// recommendation exemption.
+	CurrentModule string
+	BlockedRules  RuleListExplanation
+	AllowedRules  RuleListExplanation
+	// RecommendationExemption is true when the winning blocked rule does not
+	// apply because the current module is one of its recommendations.
+	RecommendationExemption bool
+	// Undetermined is true when no version is known and the version
+	// constraint of the deciding rule could change the verdict: that of the
+	// winning blocked rule, or that of the winning allowed rule when no
+	// blocked rule applies.
+	Undetermined bool
+	// Blocked is true when the module is blocked, Reason and RuleID are then
+	// those of the issues reported for it.
+	Blocked bool
+	Reason  string
+	RuleID  string
+}
+
+// RuleListExplanation describes the rules of the allowed or blocked list that
+// match a module.
+type RuleListExplanation struct {
+	// Configured is false when the list has no rules.
+	Configured bool
+	// Candidates are the matching rules in evaluation order, the first one
+	// wins.
+	Candidates []RuleCandidate
+	// Version is the evaluation of the version constraint of the winning
+	// rule, nil when no rule matches.
+	Version *VersionEvaluation
+}
+
+// RuleCandidate is a rule that matches a module.
+type RuleCandidate struct {
+	Rule      string
+	RuleID    string
+	MatchType MatchType
+	// Tier is the precedence tier of the rule: 1 for exact, 2 for prefix and
+	// 3 for regex rules.
+	Tier int
+}
+
+// VersionEvaluation is the result of checking a version against the version
+// constraint of a rule.
+type VersionEvaluation struct {
+	// Constraint is empty when the rule has no version constraint and
+	// applies to every version.
+	Constraint string
+	// Evaluated is false when the version is unknown or cannot be parsed.
+	Evaluated bool
+	// Satisfied is true when the version meets the constraint.
+	Satisfied bool
+	Err       error
+}
+
+// Explain describes which allowed and blocked rules match the module, which
+// of them wins and how its version constraint evaluates for moduleVersion.
+// When moduleVersion is empty the version required in go.mod is used, if any.
+func (p *Processor) Explain(moduleName, moduleVersion string) Explanation {
+	e := Explanation{
+		Module:        moduleName,
+		Version:       moduleVersion,
+		CurrentModule: p.Modfile.Module.Mod.Path,
+		BlockedRules:  RuleListExplanation{Configured: len(p.Config.Blocked) > 0},
+		AllowedRules:  RuleListExplanation{Configured: len(p.Config.Allowed) > 0},
+	}
+
+	if e.Version == "" {
+		for _, r := range p.Modfile.Require {
+			if r.Mod.Path == moduleName {
+				e.Version, e.VersionFromModFile = r.Mod.Version, true
+			}
+		}
+	}
+
+	for _, key := range p.blockedIdx.candidates(moduleName) {
+		rule := p.blockedLookup[key]
+		e.BlockedRules.Candidates = append(e.BlockedRules.Candidates, newRuleCandidate(key, rule.RuleID(), rule.MatchType))
+	}
+
+	for _, key := range p.allowedIdx.candidates(moduleName) {
+		rule := p.allowedLookup[key]
+		e.AllowedRules.Candidates = append(e.AllowedRules.Candidates, newRuleCandidate(key, rule.RuleID(), rule.MatchType))
+	}
+
+	// The winning blocked rule decides unless it is exempted or waived, only
+	// then does the version constraint of the winning allowed rule matter.
+	blockedRuleApplies := false
+
+	if len(e.BlockedRules.Candidates) > 0 {
+		rule := p.blockedLookup[e.BlockedRules.Candidates[0].Rule]
+		e.BlockedRules.Version = evaluateVersion(rule.Version, rule.CheckVersion, e.Version)
+		e.RecommendationExemption = rule.IsCurrentModuleARecommendation(e.CurrentModule)
+		blockedRuleApplies = !e.RecommendationExemption &&
+			(rule.AllowUntil == nil || rule.AllowUntil.passed(p.Config.now()))
+	}
+
+	if len(e.AllowedRules.Candidates) > 0 {
+		rule := p.allowedLookup[e.AllowedRules.Candidates[0].Rule]
+		e.AllowedRules.Version = evaluateVersion(rule.Version, rule.CheckVersion, e.Version)
+	}
+
+	if e.Version == "" {
+		deciding := e.AllowedRules.Version
+		if blockedRuleApplies {
+			deciding = e.BlockedRules.Version
+		}
+
+		if hasConstraint(deciding) {
+			e.Undetermined = true
+			return e
+		}
+	}
+
+	if blocked, ok := p.checkModule(moduleName, e.Version); ok {
+		e.Blocked, e.Reason, e.RuleID = true, blocked.reason, blocked.ruleID
+	}
+
+	return e
+}
+
+// candidates returns the keys of every rule matching moduleName in the order
+// the rules are evaluated, so the first key is the one bestMatch returns.
+func (idx *ruleIndex) candidates(moduleName string) []string {
+	var keys []string
+
+	if key, ok := idx.exactLookup[strings.TrimSpace(moduleName)]; ok {
+		keys = append(keys, key)
+	}
+
+	for _, key := range idx.prefixKeys {
+		if idx.matchers[key].Match(moduleName) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range idx.regexKeys {
+		if idx.matchers[key].Match(moduleName) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func newRuleCandidate(key, ruleID string, matchType MatchType) RuleCandidate {
+	tier := 1
+
+	switch matchType {
+	case PrefixMatch:
+		tier = 2
+	case RegexMatch:
+		tier = 3
+	}
+
+	return RuleCandidate{Rule: key, RuleID: ruleID, MatchType: matchType.orDefault(), Tier: tier}
+}
+
+// evaluateVersion checks the version against the constraint of a rule.
+func evaluateVersion(constraint *semver.Constraints, check func(string) (bool, error), version string) *VersionEvaluation {
+	if constraint == nil {
+		return &VersionEvaluation{Evaluated: true, Satisfied: true}
+	}
+
+	evaluation := &VersionEvaluation{Constraint: constraint.String()}
+	if version == "" {
+		return evaluation
+	}
+
+	evaluation.Satisfied, evaluation.Err = check(version)
+	evaluation.Evaluated = evaluation.Err == nil
+
+	return evaluation
+}
+
+func hasConstraint(evaluation *VersionEvaluation) bool {
+	return evaluation != nil && evaluation.Constraint != ""
+}
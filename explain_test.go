@@ -0,0 +1,170 @@
+package gomodguard_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorExplain(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	config := &gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+			{Module: "golang.org/x", MatchType: gomodguard.PrefixMatch},
+			{Module: "golang.org/x/mod"},
+			{Module: `^golang\.org/x/.*$`, MatchType: gomodguard.RegexMatch},
+		},
+		Blocked: gomodguard.Blocked{
+			{
+				Module:          "github.com/gofrs/uuid",
+				Recommendations: []string{"github.com/ryancurrah/gomodguard/examples/alloptions"},
+			},
+			{Module: "github.com/mitchellh/go-homedir", Version: mustConstraint(t, "<= 1.1.0")},
+		},
+	}
+
+	processor, err := gomodguard.NewProcessor(config)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		module          string
+		version         string
+		wantVersion     string
+		wantFromModFile bool
+		wantBlocked     []string
+		wantAllowed     []string
+		wantExemption   bool
+		wantUndecided   bool
+		wantRuleID      string
+	}{
+		"exact rule wins over prefixes and regexes": {
+			module:      "golang.org/x/mod",
+			version:     "v0.1.0",
+			wantVersion: "v0.1.0",
+			wantAllowed: []string{
+				"golang.org/x/mod",
+				"golang.org/x",
+				"golang.org",
+				`^golang\.org/x/.*$`,
+			},
+		},
+		"longest prefix wins": {
+			module:      "golang.org/x/tools",
+			version:     "v0.1.0",
+			wantVersion: "v0.1.0",
+			wantAllowed: []string{"golang.org/x", "golang.org", `^golang\.org/x/.*$`},
+		},
+		"version from go.mod": {
+			module:          "github.com/mitchellh/go-homedir",
+			wantVersion:     "v1.1.0",
+			wantFromModFile: true,
+			wantBlocked:     []string{"github.com/mitchellh/go-homedir"},
+			wantRuleID:      "blocked/github.com/mitchellh/go-homedir",
+		},
+		"version not meeting the blocked constraint": {
+			module:      "github.com/mitchellh/go-homedir",
+			version:     "v1.2.0",
+			wantVersion: "v1.2.0",
+			wantBlocked: []string{"github.com/mitchellh/go-homedir"},
+			wantRuleID:  "not-allowed",
+		},
+		"recommendation exemption": {
+			module:          "github.com/gofrs/uuid",
+			wantVersion:     "v3.3.0+incompatible",
+			wantFromModFile: true,
+			wantBlocked:     []string{"github.com/gofrs/uuid"},
+			wantExemption:   true,
+			wantRuleID:      "not-allowed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := processor.Explain(tt.module, tt.version)
+
+			assert.Equal(t, tt.wantVersion, e.Version)
+			assert.Equal(t, tt.wantFromModFile, e.VersionFromModFile)
+			assert.Equal(t, tt.wantBlocked, candidateRules(e.BlockedRules.Candidates))
+			assert.Equal(t, tt.wantAllowed, candidateRules(e.AllowedRules.Candidates))
+			assert.Equal(t, tt.wantExemption, e.RecommendationExemption)
+			assert.Equal(t, tt.wantUndecided, e.Undetermined)
+			assert.Equal(t, tt.wantRuleID != "", e.Blocked)
+			assert.Equal(t, tt.wantRuleID, e.RuleID)
+		})
+	}
+}
+
+func TestProcessorExplainUndetermined(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{{Module: "github.com/foo/bar", Version: mustConstraint(t, "< 2.0.0")}},
+	})
+	require.NoError(t, err)
+
+	e := processor.Explain("github.com/foo/bar", "")
+	assert.True(t, e.Undetermined)
+	assert.False(t, e.Blocked)
+	require.NotNil(t, e.BlockedRules.Version)
+	assert.Equal(t, "<2.0.0", e.BlockedRules.Version.Constraint)
+	assert.False(t, e.BlockedRules.Version.Evaluated)
+	assert.False(t, e.AllowedRules.Configured)
+}
+
+func TestProcessorExplainUndeterminedDecidingRule(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{Module: "github.com/foo", MatchType: gomodguard.PrefixMatch, Version: mustConstraint(t, ">= 1.0.0")},
+		},
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/foo/blocked"},
+			{Module: "github.com/foo/exempted", Recommendations: []string{"github.com/ryancurrah/gomodguard/examples/alloptions"}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		module           string
+		wantUndetermined bool
+		wantRuleID       string
+	}{
+		"blocked rule without version constraint": {
+			module:     "github.com/foo/blocked",
+			wantRuleID: "blocked/github.com/foo/blocked",
+		},
+		"exempted blocked rule": {
+			module:           "github.com/foo/exempted",
+			wantUndetermined: true,
+		},
+		"allowed rule with version constraint": {
+			module:           "github.com/foo/bar",
+			wantUndetermined: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := processor.Explain(tt.module, "")
+
+			assert.Equal(t, tt.wantUndetermined, e.Undetermined)
+			assert.Equal(t, tt.wantRuleID != "", e.Blocked)
+			assert.Equal(t, tt.wantRuleID, e.RuleID)
+		})
+	}
+}
+
+func candidateRules(candidates []gomodguard.RuleCandidate) []string {
+	var rules []string
+	for _, c := range candidates {
+		rules = append(rules, c.Rule)
+	}
+
+	return rules
+}
@@ -0,0 +1,179 @@
+package gomodguard
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// majorVersionSuffix matches the major version suffix of an import path, e.g.
+// /v2 of github.com/foo/bar/v2 or .v3 of gopkg.in/yaml.v3.
+var majorVersionSuffix = regexp.MustCompile(`[/.]v[0-9]+$`)
+
+// TextEdit replaces the source between Pos and End with NewText.
+type TextEdit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+}
+
+// FileFix holds the source of a file before and after rewriting its imports.
+type FileFix struct {
+	FileName string
+	Source   []byte
+	Fixed    []byte
+}
+
+// validateRewrites returns an error when a rewrite of a blocked rule is not
+// between valid import paths.
+func (c *Configuration) validateRewrites() error {
+	for i := range c.Blocked {
+		for from, to := range c.Blocked[i].Rewrite {
+			for _, importPath := range []string{from, to} {
+				if err := module.CheckImportPath(importPath); err != nil {
+					return fmt.Errorf("invalid rewrite of '%s' to '%s' for blocked rule '%s': %w",
+						from, to, c.Blocked[i].Module, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// rewriteImportPath returns the import path that the rewrite mapping rewrites
+// importPath to. The longest key that is importPath or one of its parent
+// paths is replaced by its value.
+func rewriteImportPath(rewrite map[string]string, importPath string) (string, bool) {
+	var from string
+
+	for key := range rewrite {
+		if (importPath == key || strings.HasPrefix(importPath, key+"/")) && len(key) > len(from) {
+			from = key
+		}
+	}
+
+	if from == "" {
+		return "", false
+	}
+
+	return rewrite[from] + strings.TrimPrefix(importPath, from), true
+}
+
+// importName guesses the package name of an import path from its last
+// element, ignoring major version suffixes.
+func importName(importPath string) string {
+	name := path.Base(majorVersionSuffix.ReplaceAllString(importPath, ""))
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	return name
+}
+
+// RewriteEdit returns the edit rewriting the import reported by the issue to
+// the import path of its Rewrite. Only the name and path of the import spec
+// are replaced, so grouping and comments are preserved. An existing name is
+// kept, and the previous package name is added as name when the package name
+// of the new path differs. No edit is returned when the file already imports
+// the new path, as rewriting would duplicate the import.
+func RewriteEdit(fileSet *token.FileSet, file *ast.File, issue Issue) (TextEdit, bool) {
+	if issue.Rewrite == "" || isImported(file, issue.Rewrite) {
+		return TextEdit{}, false
+	}
+
+	for _, spec := range file.Imports {
+		if fileSet.Position(spec.Pos()).Offset != issue.Position.Offset {
+			continue
+		}
+
+		newText := strconv.Quote(issue.Rewrite)
+
+		switch {
+		case spec.Name != nil:
+			newText = spec.Name.Name + " " + newText
+		case importName(issue.Package) != importName(issue.Rewrite) && token.IsIdentifier(importName(issue.Package)):
+			newText = importName(issue.Package) + " " + newText
+		}
+
+		return TextEdit{Pos: spec.Pos(), End: spec.Path.End(), NewText: []byte(newText)}, true
+	}
+
+	return TextEdit{}, false
+}
+
+// isImported returns true when the file imports importPath.
+func isImported(file *ast.File, importPath string) bool {
+	return slices.ContainsFunc(file.Imports, func(spec *ast.ImportSpec) bool {
+		p, err := strconv.Unquote(spec.Path.Value)
+		return err == nil && p == importPath
+	})
+}
+
+// FixFiles rewrites the imports of the files that blocked rules with a
+// rewrite mapping report, and returns the fixes of the files that changed.
+// Suppressed imports are not rewritten. Files that cannot be read or parsed
+// are skipped, linting them reports why.
+func (p *Processor) FixFiles(filenames []string) (fixes []FileFix) {
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			continue
+		}
+
+		fileSet := token.NewFileSet()
+
+		file, err := parser.ParseFile(fileSet, filename, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		var edits []TextEdit
+
+		for _, issue := range p.ProcessFile(fileSet, file) {
+			if edit, ok := RewriteEdit(fileSet, file, issue); ok {
+				edits = append(edits, edit)
+			}
+		}
+
+		if len(edits) > 0 {
+			fixes = append(fixes, FileFix{FileName: filename, Source: src, Fixed: applyEdits(fileSet, src, edits)})
+		}
+	}
+
+	return fixes
+}
+
+// applyEdits returns src with the edits applied, edits overlapping a
+// previous edit are dropped.
+func applyEdits(fileSet *token.FileSet, src []byte, edits []TextEdit) []byte {
+	slices.SortFunc(edits, func(a, b TextEdit) int { return int(a.Pos - b.Pos) })
+
+	var (
+		fixed []byte
+		last  int
+	)
+
+	for _, edit := range edits {
+		pos, end := fileSet.Position(edit.Pos).Offset, fileSet.Position(edit.End).Offset
+		if pos < last {
+			// Several blocked rules may rewrite the same import, the first wins.
+			continue
+		}
+
+		fixed = append(fixed, src[last:pos]...)
+		fixed = append(fixed, edit.NewText...)
+		last = end
+	}
+
+	return append(fixed, src[last:]...)
+}
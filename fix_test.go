@@ -0,0 +1,135 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorFixFiles(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:  "github.com/gofrs/uuid",
+				Rewrite: map[string]string{"github.com/gofrs/uuid": "example.com/ids"},
+			},
+			{
+				Module: "github.com/mitchellh/go-homedir",
+				Rewrite: map[string]string{
+					"github.com/mitchellh":            "example.com/mitchellh",
+					"github.com/mitchellh/go-homedir": "example.com/home",
+				},
+			},
+			{Module: "golang.org/x/mod"},
+		},
+	})
+	require.NoError(t, err)
+
+	src := `package a
+
+import (
+	"fmt"
+
+	// UUIDs of the records.
+	"github.com/gofrs/uuid" // v3 only
+	hd "github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/go-homedir/sub"
+	"golang.org/x/mod/semver"
+)
+`
+	want := `package a
+
+import (
+	"fmt"
+
+	// UUIDs of the records.
+	uuid "example.com/ids" // v3 only
+	hd "example.com/home"
+	"example.com/home/sub"
+	"golang.org/x/mod/semver"
+)
+`
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "a.go")
+	unchanged := filepath.Join(dir, "b.go")
+
+	require.NoError(t, os.WriteFile(filename, []byte(src), 0o600))
+	require.NoError(t, os.WriteFile(unchanged, []byte("package a\n\nimport \"fmt\"\n"), 0o600))
+
+	fixes := processor.FixFiles([]string{filename, unchanged, filepath.Join(dir, "missing.go")})
+	require.Len(t, fixes, 1)
+
+	assert.Equal(t, filename, fixes[0].FileName)
+	assert.Equal(t, src, string(fixes[0].Source))
+	assert.Equal(t, want, string(fixes[0].Fixed))
+}
+
+func TestProcessorFixFilesAlreadyImported(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:  "github.com/gofrs/uuid",
+				Rewrite: map[string]string{"github.com/gofrs/uuid": "example.com/ids"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "a.go")
+	src := "package a\n\nimport (\n\t\"example.com/ids\"\n\t\"github.com/gofrs/uuid\"\n)\n"
+
+	require.NoError(t, os.WriteFile(filename, []byte(src), 0o600))
+
+	assert.Empty(t, processor.FixFiles([]string{filename}))
+}
+
+func TestProcessorIssueRewrite(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:  "github.com/gofrs/uuid",
+				Rewrite: map[string]string{"github.com/gofrs/uuid": "github.com/google/uuid"},
+			},
+			{Module: "github.com/mitchellh/go-homedir"},
+		},
+	})
+	require.NoError(t, err)
+
+	rewrites := map[string]string{}
+	for _, issue := range processor.ProcessFiles(gomodguard.Find(".", false, []string{"./..."})) {
+		rewrites[issue.Package] = issue.Rewrite
+	}
+
+	assert.Equal(t, map[string]string{
+		"github.com/gofrs/uuid":           "github.com/google/uuid",
+		"github.com/mitchellh/go-homedir": "",
+	}, rewrites)
+}
+
+func TestProcessorNewProcessorInvalidRewrite(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	_, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:  "github.com/gofrs/uuid",
+				Rewrite: map[string]string{"github.com/gofrs/uuid": "github.com/google/uuid "},
+			},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"invalid rewrite of 'github.com/gofrs/uuid' to 'github.com/google/uuid ' for blocked rule 'github.com/gofrs/uuid'")
+}
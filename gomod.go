@@ -0,0 +1,172 @@
+package gomodguard
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// IncompleteModuleGraphRuleID is the rule ID of the warning reported when
+// transitive dependencies cannot be fully checked because go.mod files are
+// missing from the module cache.
+const IncompleteModuleGraphRuleID = "incomplete-module-graph"
+
+// ProcessModFile lints the go.mod file of the module and returns issues
+// positioned at its directives.
+//
+// When the configuration enables transitive checks, blocked modules that are
+// only required indirectly are reported on the require line of each direct
+// dependency that introduces them. Rules about to expire are reported as
+// warnings on the require line of the modules they match, replace directives
+// blocked by the replace policy on their replace line, go and toolchain
+// directives not meeting the go version policy on their line and tools of
+// blocked modules or missing from the allowed tools list on their tool line.
+func (p *Processor) ProcessModFile() (issues []Issue) {
+	if p.Config.Transitive {
+		issues = append(issues, p.processTransitive(goModCacheDir())...)
+	}
+
+	issues = append(issues, p.processExpiries()...)
+	issues = append(issues, p.processReplaces()...)
+
+	issues = append(issues, p.processGoVersion()...)
+
+	return append(issues, p.processTools()...)
+}
+
+// ProcessRequires returns an issue positioned at the require line of every
+// blocked or not allowed direct requirement of the go.mod file, whether or not
+// it is imported. Blocked replace directives are reported by ProcessModFile.
+//
+// Requirements marked `// indirect` are not considered: they are not chosen by
+// the module but by its dependencies, so with transitive checks enabled
+// ProcessModFile reports them at the direct requirement introducing them.
+func (p *Processor) ProcessRequires() (issues []Issue) {
+	for _, r := range p.Modfile.Require {
+		if r.Indirect {
+			continue
+		}
+
+		for _, blocked := range p.blockedModulesFromModFile[r.Mod.Path] {
+			if blocked.isReplace() {
+				continue
+			}
+
+			issues = append(issues, p.addModFileError(r.Syntax,
+				fmt.Sprintf("requirement of module `%s` is blocked because %s", r.Mod.Path, blocked.reason),
+				blocked,
+			))
+		}
+	}
+
+	return issues
+}
+
+// processTransitive reports the blocked modules in the module graph of each
+// direct dependency.
+func (p *Processor) processTransitive(cacheDir string) (issues []Issue) {
+	graph := p.loadModuleGraph(cacheDir)
+
+	direct := make(map[string]bool, len(p.Modfile.Require))
+	for _, r := range p.Modfile.Require {
+		if !r.Indirect {
+			direct[r.Mod.Path] = true
+		}
+	}
+
+	for _, r := range p.Modfile.Require {
+		if r.Indirect {
+			continue
+		}
+
+		paths := graph.paths(r.Mod)
+
+		modulePaths := make([]string, 0, len(paths))
+		for modulePath := range paths {
+			if !direct[modulePath] && modulePath != p.Modfile.Module.Mod.Path {
+				modulePaths = append(modulePaths, modulePath)
+			}
+		}
+
+		slices.Sort(modulePaths)
+
+		for _, modulePath := range modulePaths {
+			blocked, ok := p.checkBlockedList(modulePath, graph.selected[modulePath])
+			if !ok {
+				continue
+			}
+
+			issue := p.addModFileError(r.Syntax,
+				fmt.Sprintf("dependency `%s` requires module `%s` (%s) which is blocked because %s",
+					r.Mod.Path, modulePath, strings.Join(paths[modulePath], " -> "), blocked.reason,
+				),
+				blocked,
+			)
+			issue.DependencyPath = paths[modulePath]
+
+			issues = append(issues, issue)
+		}
+	}
+
+	if len(graph.missing) > 0 {
+		missing := make([]string, 0, len(graph.missing))
+		for _, mod := range graph.missing {
+			missing = append(missing, mod.String())
+		}
+
+		slices.Sort(missing)
+
+		issues = append(issues, Issue{
+			FileName: p.modFileName(),
+			Reason: fmt.Sprintf("unable to read the go.mod file of %d modules from the module cache, transitive "+
+				"dependencies cannot be fully checked, run `go mod download` (%s)",
+				len(missing), strings.Join(missing, ", ")),
+			Kind:     IssueKindIncompleteModuleGraph,
+			RuleID:   IncompleteModuleGraphRuleID,
+			Severity: SeverityWarning,
+		})
+	}
+
+	return issues
+}
+
+// addModFileError returns an issue positioned at a directive of the go.mod file.
+func (p *Processor) addModFileError(line *modfile.Line, reason string, blocked blockedModule) Issue {
+	position := token.Position{Filename: p.modFileName()}
+	if line != nil {
+		position.Line = line.Start.Line
+		position.Column = line.Start.LineRune
+	}
+
+	return Issue{
+		FileName:        position.Filename,
+		LineNumber:      position.Line,
+		Position:        position,
+		Reason:          reason,
+		Kind:            blocked.kind,
+		Module:          blocked.module,
+		Version:         blocked.version,
+		Rule:            blocked.rule,
+		RuleID:          blocked.ruleID,
+		MatchType:       blocked.matchType,
+		Recommendations: blocked.recommendations,
+		Severity:        blocked.severity,
+	}
+}
+
+// modFileName returns the path of the go.mod file relative to the working
+// directory when possible, like the file names returned by Find.
+func (p *Processor) modFileName() string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, p.modFilePath); err == nil {
+			return rel
+		}
+	}
+
+	return p.modFilePath
+}
@@ -0,0 +1,152 @@
+package gomodguard_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorProcessModFileTransitive(t *testing.T) {
+	modCacheDir, err := filepath.Abs("examples/transitive/modcache")
+	require.NoError(t, err)
+
+	t.Setenv("GOMODCACHE", modCacheDir)
+	t.Chdir("examples/transitive")
+
+	tests := map[string]struct {
+		transitive bool
+		want       []string
+	}{
+		"transitive disabled": {
+			transitive: false,
+			want:       []string{},
+		},
+		"transitive enabled": {
+			transitive: true,
+			want: []string{
+				"go.mod:5:1 dependency `example.com/direct` requires module `example.com/blocked` " +
+					"(example.com/direct -> example.com/middle -> example.com/blocked) which is blocked because " +
+					"the module is in the blocked modules list. testing transitive blocking. (blocked/example.com/blocked)",
+				"go.mod:0:1 warning: unable to read the go.mod file of 1 modules from the module cache, transitive " +
+					"dependencies cannot be fully checked, run `go mod download` (example.com/missing@v1.0.0) " +
+					"(incomplete-module-graph)",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+				Blocked: gomodguard.Blocked{
+					{Module: "example.com/blocked", Reason: "testing transitive blocking."},
+				},
+				Transitive: tt.transitive,
+			})
+			require.NoError(t, err)
+
+			issues := processor.ProcessModFile()
+
+			got := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, issue.String())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcessorProcessModFileTransitiveMetadata(t *testing.T) {
+	modCacheDir, err := filepath.Abs("examples/transitive/modcache")
+	require.NoError(t, err)
+
+	t.Setenv("GOMODCACHE", modCacheDir)
+	t.Chdir("examples/transitive")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{Module: "example.com/blocked", Reason: "testing transitive blocking."},
+		},
+		Transitive: true,
+	})
+	require.NoError(t, err)
+
+	issues := processor.ProcessModFile()
+	require.NotEmpty(t, issues)
+
+	assert.Equal(t, gomodguard.IssueKindBlocked, issues[0].Kind)
+	assert.Equal(t, "example.com/blocked", issues[0].Module)
+	assert.Equal(t, "v1.3.0", issues[0].Version)
+	assert.Equal(t, "blocked/example.com/blocked", issues[0].RuleID)
+	assert.Empty(t, issues[0].Package)
+	assert.Equal(t, []string{"example.com/direct", "example.com/middle", "example.com/blocked"}, issues[0].DependencyPath)
+}
+
+func TestProcessorProcessRequires(t *testing.T) {
+	tests := map[string]struct {
+		exampleDir string
+		config     *gomodguard.Configuration
+		want       []string
+	}{
+		"blocked and not allowed requirements": {
+			exampleDir: "examples/alloptions",
+			config: &gomodguard.Configuration{
+				Allowed: gomodguard.Allowed{
+					{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+					{Module: "github.com/uudashr/go-module"},
+				},
+				Blocked: gomodguard.Blocked{
+					{Module: "github.com/uudashr/go-module", Reason: "testing require line issues."},
+				},
+			},
+			want: []string{
+				"go.mod:6:1 requirement of module `github.com/gofrs/uuid` is blocked because the module is not " +
+					"in the allowed modules list. (not-allowed)",
+				"go.mod:7:1 requirement of module `github.com/mitchellh/go-homedir` is blocked because the module " +
+					"is not in the allowed modules list. (not-allowed)",
+				"go.mod:8:1 requirement of module `github.com/uudashr/go-module` is blocked because the module is " +
+					"in the blocked modules list. testing require line issues. (blocked/github.com/uudashr/go-module)",
+			},
+		},
+		"indirect requirements are not considered": {
+			exampleDir: "examples/indirectdep",
+			config: &gomodguard.Configuration{
+				Allowed: gomodguard.Allowed{{Module: "github.com/mitchellh/go-homedir"}},
+				Blocked: gomodguard.Blocked{{Module: "github.com/gofrs/uuid"}},
+			},
+			want: []string{},
+		},
+		"local replace directive is left to ProcessModFile": {
+			exampleDir: "examples/localreplace_nomod",
+			config:     &gomodguard.Configuration{LocalReplaceDirectives: true},
+			want:       []string{},
+		},
+		"no issues": {
+			exampleDir: "examples/alloptions",
+			config:     &gomodguard.Configuration{},
+			want:       []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(tt.exampleDir)
+
+			processor, err := gomodguard.NewProcessor(tt.config)
+			require.NoError(t, err)
+
+			issues := processor.ProcessRequires()
+
+			got := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, issue.String())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package gomodguard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	// GoDirectiveRuleID is the rule ID of issues for a go directive that does
+	// not meet the go version constraint.
+	GoDirectiveRuleID = goVersionRuleIDPrefix + "/go"
+	// ToolchainDirectiveRuleID is the rule ID of issues for a toolchain
+	// directive that does not meet the toolchain version constraint.
+	ToolchainDirectiveRuleID = goVersionRuleIDPrefix + "/toolchain"
+
+	goVersionRuleIDPrefix = "go-version"
+
+	// defaultGoVersion is the Go language version of a go.mod file without a
+	// go directive.
+	defaultGoVersion = "1.16"
+)
+
+// goVersionSyntax matches Go versions such as 1.21, 1.21.3 and 1.21rc1,
+// optionally prefixed with go as in toolchain names and followed by a custom
+// toolchain suffix such as -custom or +auto.
+var goVersionSyntax = regexp.MustCompile(`^(?:go)?(\d+(?:\.\d+){0,2})(?:(alpha|beta|rc)(\d+))?(?:[-+].*)?$`)
+
+// GoVersionPolicy holds the version constraints for the go and toolchain
+// directives of go.mod.
+type GoVersionPolicy struct {
+	// Go constrains the Go language version of the go directive, e.g. >= 1.24.
+	Go *semver.Constraints `yaml:"go,omitempty"`
+	// Toolchain constrains the version of the toolchain directive, e.g.
+	// >= 1.24.2. Without a toolchain directive the go directive's version is
+	// the toolchain version.
+	Toolchain *semver.Constraints `yaml:"toolchain,omitempty"`
+	Reason    string              `yaml:"reason,omitempty"`
+	// Severity is the severity of the issues for the directives, it defaults
+	// to error.
+	Severity Severity `yaml:"severity,omitempty"`
+}
+
+// withParent returns the policy with the unset fields inherited from parent.
+func (g GoVersionPolicy) withParent(parent GoVersionPolicy) GoVersionPolicy {
+	if g.Go == nil {
+		g.Go = parent.Go
+	}
+
+	if g.Toolchain == nil {
+		g.Toolchain = parent.Toolchain
+	}
+
+	if g.Reason == "" {
+		g.Reason = parent.Reason
+	}
+
+	if g.Severity == "" {
+		g.Severity = parent.Severity
+	}
+
+	return g
+}
+
+// parseGoVersion returns the semantic version of a Go version or toolchain
+// name, e.g. 1.21rc1 is 1.21.0-rc.1 and go1.24.2 is 1.24.2. The number of the
+// pre-release is a separate identifier so that rc10 is higher than rc2.
+func parseGoVersion(version string) (*semver.Version, error) {
+	m := goVersionSyntax.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, fmt.Errorf("invalid Go version `%s`", version)
+	}
+
+	if m[2] != "" {
+		return semver.NewVersion(m[1] + "-" + m[2] + "." + m[3])
+	}
+
+	return semver.NewVersion(m[1])
+}
+
+// processGoVersion returns an issue positioned at the go and the toolchain
+// directives of go.mod when they do not meet the go version policy. A
+// missing go directive is reported at the module directive.
+func (p *Processor) processGoVersion() (issues []Issue) {
+	policy := p.Config.GoVersion
+
+	goVersion, goLine := defaultGoVersion, (*modfile.Line)(nil)
+	if p.Modfile.Module != nil {
+		goLine = p.Modfile.Module.Syntax
+	}
+
+	if p.Modfile.Go != nil {
+		goVersion, goLine = p.Modfile.Go.Version, p.Modfile.Go.Syntax
+	}
+
+	toolchain, toolchainLine := "go"+goVersion, goLine
+	if p.Modfile.Toolchain != nil {
+		toolchain, toolchainLine = p.Modfile.Toolchain.Name, p.Modfile.Toolchain.Syntax
+	}
+
+	if policy.Go != nil {
+		if issue, ok := p.checkGoVersion("go directive", goVersion, policy.Go, goLine, GoDirectiveRuleID); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	if policy.Toolchain != nil {
+		if issue, ok := p.checkGoVersion("toolchain", toolchain, policy.Toolchain, toolchainLine, ToolchainDirectiveRuleID); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// checkGoVersion returns an issue when the version does not meet the
+// constraint.
+func (p *Processor) checkGoVersion(directive, version string, constraint *semver.Constraints,
+	line *modfile.Line, ruleID string,
+) (Issue, bool) {
+	var reason string
+
+	v, err := parseGoVersion(version)
+
+	// Like the go command, a release candidate is ordered before its release
+	// and after the earlier releases, rather than meeting no constraint
+	// without a pre-release.
+	withPrerelease := *constraint
+	withPrerelease.IncludePrerelease = true
+
+	switch {
+	case err != nil:
+		reason = fmt.Sprintf("%s `%s` is blocked because %s.", directive, version, err)
+	case !withPrerelease.Check(v):
+		reason = fmt.Sprintf("%s `%s` is blocked because it does not meet the version constraint `%s`.",
+			directive, version, constraint)
+	default:
+		return Issue{}, false
+	}
+
+	if p.Config.GoVersion.Reason != "" {
+		reason += " " + p.Config.GoVersion.Reason
+	}
+
+	return p.addModFileError(line, reason, blockedModule{
+		kind:     IssueKindGoVersion,
+		version:  version,
+		ruleID:   ruleID,
+		severity: p.Config.GoVersion.Severity.orDefault(),
+	}), true
+}
@@ -0,0 +1,123 @@
+package gomodguard_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorProcessModFileGoVersion(t *testing.T) { //nolint:funlen
+	tests := map[string]struct {
+		goMod  string
+		policy gomodguard.GoVersionPolicy
+		want   []string
+	}{
+		"versions meet the constraints": {
+			goMod: "module example.com/m\n\ngo 1.24.0\n\ntoolchain go1.24.2\n",
+			policy: gomodguard.GoVersionPolicy{
+				Go:        mustConstraint(t, ">= 1.24"),
+				Toolchain: mustConstraint(t, ">= 1.24.2"),
+			},
+			want: []string{},
+		},
+		"versions do not meet the constraints": {
+			goMod: "module example.com/m\n\ngo 1.22\n\ntoolchain go1.23.4\n",
+			policy: gomodguard.GoVersionPolicy{
+				Go:        mustConstraint(t, ">= 1.24"),
+				Toolchain: mustConstraint(t, ">= 1.24.2"),
+				Reason:    "The platform requires Go 1.24.",
+				Severity:  gomodguard.SeverityWarning,
+			},
+			want: []string{
+				"3 warning go-version go-version/go: go directive `1.22` is blocked because it does not meet the " +
+					"version constraint `>=1.24`. The platform requires Go 1.24.",
+				"5 warning go-version go-version/toolchain: toolchain `go1.23.4` is blocked because it does not meet " +
+					"the version constraint `>=1.24.2`. The platform requires Go 1.24.",
+			},
+		},
+		"toolchain defaults to the go version": {
+			goMod: "module example.com/m\n\ngo 1.24rc1\n",
+			policy: gomodguard.GoVersionPolicy{
+				Toolchain: mustConstraint(t, ">= 1.24.0"),
+			},
+			want: []string{
+				"3 error go-version go-version/toolchain: toolchain `go1.24rc1` is blocked because it does not meet " +
+					"the version constraint `>=1.24.0`.",
+			},
+		},
+		"newer release candidates meet a lower minimum": {
+			goMod: "module example.com/m\n\ngo 1.25rc1\n\ntoolchain go1.25rc2\n",
+			policy: gomodguard.GoVersionPolicy{
+				Go:        mustConstraint(t, ">= 1.24"),
+				Toolchain: mustConstraint(t, ">= 1.24.2"),
+			},
+			want: []string{},
+		},
+		"release candidates are ordered by number": {
+			goMod: "module example.com/m\n\ngo 1.25rc2\n\ntoolchain go1.25rc10\n",
+			policy: gomodguard.GoVersionPolicy{
+				Go:        mustConstraint(t, "< 1.25"),
+				Toolchain: mustConstraint(t, "< 1.25.0-rc.3"),
+			},
+			want: []string{
+				"5 error go-version go-version/toolchain: toolchain `go1.25rc10` is blocked because it does not meet " +
+					"the version constraint `<1.25.0-rc.3`.",
+			},
+		},
+		"custom toolchain suffix": {
+			goMod: "module example.com/m\n\ngo 1.24.0\n\ntoolchain go1.24.2-custom\n",
+			policy: gomodguard.GoVersionPolicy{
+				Toolchain: mustConstraint(t, ">= 1.24.2"),
+			},
+			want: []string{},
+		},
+		"go version defaults to 1.16": {
+			goMod: "module example.com/m\n",
+			policy: gomodguard.GoVersionPolicy{
+				Go: mustConstraint(t, ">= 1.21"),
+			},
+			want: []string{
+				"1 error go-version go-version/go: go directive `1.16` is blocked because it does not meet the " +
+					"version constraint `>=1.21`.",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			goModFilePath := filepath.Join(t.TempDir(), "go.mod")
+			writeFiles(t, map[string]string{goModFilePath: tt.goMod})
+
+			processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{GoVersion: tt.policy}, goModFilePath)
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, issue := range processor.ProcessModFile() {
+				got = append(got, fmt.Sprintf("%d %s %s %s: %s", issue.LineNumber, issue.Severity, issue.Kind, issue.RuleID, issue.Reason))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadConfigurationExtendsGoVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "org.yaml"):         "go-version:\n  go: \">= 1.23\"\n  toolchain: \">= 1.23.4\"\n  severity: warning\n",
+		filepath.Join(dir, ".gomodguard.yaml"): "extends:\n  - org.yaml\ngo-version:\n  go: \">= 1.24\"\n",
+	})
+
+	config, err := gomodguard.LoadConfiguration(filepath.Join(dir, ".gomodguard.yaml"), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, ">=1.24", config.GoVersion.Go.String())
+	assert.Equal(t, ">=1.23.4", config.GoVersion.Toolchain.String())
+	assert.Equal(t, gomodguard.SeverityWarning, config.GoVersion.Severity)
+}
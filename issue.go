@@ -3,18 +3,141 @@ package gomodguard
 import (
 	"fmt"
 	"go/token"
+	"strings"
+)
+
+// IssueKind is the kind of violation an Issue reports.
+type IssueKind string
+
+const (
+	// IssueKindBlocked is reported when the module is in the blocked list.
+	IssueKindBlocked IssueKind = "blocked"
+	// IssueKindNotAllowed is reported when the module is not in the allowed list.
+	IssueKindNotAllowed IssueKind = "not-allowed"
+	// IssueKindVersionConstraint is reported when the module is in the allowed list
+	// but its version does not meet the allowed version constraint.
+	IssueKindVersionConstraint IssueKind = "version-constraint"
+	// IssueKindLocalReplace is reported when the module has a local replace directive.
+	//
+	// Deprecated: local replace directives are reported as IssueKindBlockedReplace.
+	IssueKindLocalReplace IssueKind = "local-replace"
+	// IssueKindUnusedSuppression is reported for a suppression comment that does not suppress any issue.
+	IssueKindUnusedSuppression IssueKind = "unused-suppression"
+	// IssueKindSuppressionWithoutReason is reported for a suppression comment that does not give a reason.
+	IssueKindSuppressionWithoutReason IssueKind = "suppression-without-reason"
+	// IssueKindUnknownSuppressionRule is reported for a suppression comment naming rule IDs that are not the ID of any rule.
+	IssueKindUnknownSuppressionRule IssueKind = "unknown-suppression-rule"
+	// IssueKindExpired is reported when the module is only allowed by an allowed rule that expired.
+	IssueKindExpired IssueKind = "expired"
+	// IssueKindExpiring is reported as a warning when the allowed rule of a module expires, or the waiver
+	// of its blocked rule ends, within the expiry warning window.
+	IssueKindExpiring IssueKind = "expiring"
+	// IssueKindBlockedReplace is reported when a replace directive matches a blocked replace rule.
+	IssueKindBlockedReplace IssueKind = "blocked-replace"
+	// IssueKindNotAllowedReplace is reported when a replace directive is not in the allowed replaces list of its kind.
+	IssueKindNotAllowedReplace IssueKind = "not-allowed-replace"
+	// IssueKindGoVersion is reported when the go or toolchain directive does not meet the go version policy.
+	IssueKindGoVersion IssueKind = "go-version"
+	// IssueKindNotAllowedTool is reported when a tool directive is not in the allowed tools list.
+	IssueKindNotAllowedTool IssueKind = "not-allowed-tool"
+	// IssueKindIncompleteModuleGraph is reported as a warning when the go.mod file of modules in the
+	// module graph is not in the module cache, so transitive dependencies cannot be fully checked.
+	IssueKindIncompleteModuleGraph IssueKind = "incomplete-module-graph"
+)
+
+const (
+	// NotAllowedRuleID is the rule ID of issues for modules that are not in the allowed list.
+	NotAllowedRuleID = "not-allowed"
+	// LocalReplaceDirectivesRuleID is the rule ID of issues for modules with a local replace directive.
+	LocalReplaceDirectivesRuleID = "local-replace-directives"
+
+	allowedRuleIDPrefix = "allowed"
+	blockedRuleIDPrefix = "blocked"
 )
 
 // Issue represents the result of one error.
+//
+// Besides the location and the formatted Reason, an Issue describes the
+// violation in structured form so consumers do not need to parse the reason.
 type Issue struct {
 	FileName   string
 	LineNumber int
 	Position   token.Position
 	Reason     string
+	// Kind is the kind of violation, it is empty when the file could not be linted.
+	Kind IssueKind
+	// Package is the path of the imported package.
+	Package string
+	// Module is the path of the module owning the imported package.
+	Module string
+	// Version is the version of the module required in go.mod.
+	Version string
+	// Rule is the module key of the configured rule that matched the module, if any.
+	Rule string
+	// RuleID identifies the rule that reported the issue, see AllowedModule.RuleID
+	// and BlockedModule.RuleID.
+	RuleID string
+	// MatchType is the match type of Rule.
+	MatchType MatchType
+	// Recommendations are the recommended alternatives to the blocked module.
+	Recommendations []string
+	// DependencyPath is the chain of module paths from a direct dependency to
+	// the blocked module when it is only required transitively.
+	DependencyPath []string
+	// Severity is the severity of the rule that reported the issue.
+	Severity Severity
+	// Rewrite is the import path the rewrite mapping of the blocked rule
+	// rewrites the imported package to, if any.
+	Rewrite string
 }
 
 // String returns the filename, line
-// number and reason of a Issue.
+// number, reason and rule ID of a Issue. The severity precedes the reason
+// unless it is error.
 func (r *Issue) String() string {
-	return fmt.Sprintf("%s:%d:1 %s", r.FileName, r.LineNumber, r.Reason)
+	reason := r.Reason
+	if !r.IsError() {
+		reason = fmt.Sprintf("%s: %s", r.Severity, r.Reason)
+	}
+
+	if r.RuleID == "" {
+		return fmt.Sprintf("%s:%d:1 %s", r.FileName, r.LineNumber, reason)
+	}
+
+	return fmt.Sprintf("%s:%d:1 %s (%s)", r.FileName, r.LineNumber, reason, r.RuleID)
+}
+
+// EffectiveSeverity returns the severity of the issue, issues without a
+// severity are errors.
+func (r *Issue) EffectiveSeverity() Severity {
+	return r.Severity.orDefault()
+}
+
+// IsError returns true if the issue fails the lint, which is the case for
+// issues of error severity or without a severity.
+func (r *Issue) IsError() bool {
+	return r.EffectiveSeverity() == SeverityError
+}
+
+// isBuiltinRuleID returns true if id is the ID, or has the prefix of the IDs,
+// of a built-in rule, so a configured rule cannot use it.
+func isBuiltinRuleID(id string) bool {
+	switch id {
+	case NotAllowedRuleID, LocalReplaceDirectivesRuleID, NotAllowedToolRuleID, UnusedSuppressionRuleID,
+		SuppressionWithoutReasonRuleID, UnknownSuppressionRuleID, IncompleteModuleGraphRuleID:
+		return true
+	}
+
+	for _, prefix := range []string{goVersionRuleIDPrefix, replaceRuleIDPrefix, LocalReplaceDirectivesRuleID} {
+		if strings.HasPrefix(id, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deriveRuleID returns the rule ID of a rule without a configured ID.
+func deriveRuleID(prefix, module string) string {
+	return prefix + "/" + strings.TrimSpace(module)
 }
@@ -18,6 +18,37 @@ func TestResultString(t *testing.T) {
 			gomodguard.Issue{FileName: "test.go", LineNumber: 1, Reason: "Some reason."},
 			"test.go:1:1 Some reason.",
 		},
+		{
+			"structured fields do not change the output",
+			gomodguard.Issue{
+				FileName:   "test.go",
+				LineNumber: 2,
+				Reason:     "Some reason.",
+				Kind:       gomodguard.IssueKindBlocked,
+				Package:    "github.com/foo/bar/baz",
+				Module:     "github.com/foo/bar",
+				Version:    "v1.0.0",
+				Rule:       "github.com/foo/bar",
+				MatchType:  gomodguard.ExactMatch,
+			},
+			"test.go:2:1 Some reason.",
+		},
+		{
+			"rule id is appended",
+			gomodguard.Issue{FileName: "test.go", LineNumber: 3, Reason: "Some reason.", RuleID: "blocked/github.com/foo/bar"},
+			"test.go:3:1 Some reason. (blocked/github.com/foo/bar)",
+		},
+		{
+			"severity precedes the reason unless it is error",
+			gomodguard.Issue{
+				FileName:   "test.go",
+				LineNumber: 4,
+				Reason:     "Some reason.",
+				RuleID:     "blocked/github.com/foo/bar",
+				Severity:   gomodguard.SeverityWarning,
+			},
+			"test.go:4:1 warning: Some reason. (blocked/github.com/foo/bar)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -29,3 +60,29 @@ func TestResultString(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueEffectiveSeverity(t *testing.T) {
+	var tests = []struct {
+		testName     string
+		severity     gomodguard.Severity
+		wantSeverity gomodguard.Severity
+		wantIsError  bool
+	}{
+		{"no severity is an error", "", gomodguard.SeverityError, true},
+		{"error", gomodguard.SeverityError, gomodguard.SeverityError, true},
+		{"warning", gomodguard.SeverityWarning, gomodguard.SeverityWarning, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			issue := gomodguard.Issue{Severity: tt.severity}
+			if got := issue.EffectiveSeverity(); got != tt.wantSeverity {
+				t.Errorf("got severity '%s' want '%s'", got, tt.wantSeverity)
+			}
+
+			if got := issue.IsError(); got != tt.wantIsError {
+				t.Errorf("got IsError %t want %t", got, tt.wantIsError)
+			}
+		})
+	}
+}
@@ -18,6 +18,15 @@ const (
 	RegexMatch MatchType = "regex"
 )
 
+// orDefault returns the match type, or ExactMatch when none is set.
+func (t MatchType) orDefault() MatchType {
+	if t == "" {
+		return ExactMatch
+	}
+
+	return t
+}
+
 // Matcher interface for matching module names.
 type Matcher interface {
 	Match(moduleName string) bool
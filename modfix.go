@@ -0,0 +1,238 @@
+package gomodguard
+
+import (
+	"bufio"
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// RequireChange describes a requirement of go.mod changed by FixModFiles.
+type RequireChange struct {
+	Module  string
+	Version string
+	// NewVersion is the version the requirement is bumped to, it is empty
+	// when the requirement is dropped.
+	NewVersion string
+}
+
+// ModFileFix holds the go.mod file of a module before and after fixing its
+// blocked requirements.
+type ModFileFix struct {
+	FileName string
+	Source   []byte
+	Fixed    []byte
+	Changes  []RequireChange
+}
+
+// FixModFiles fixes the blocked direct requirements of the go.mod file of the
+// module. A requirement that no package of the module imports and no tool
+// directive uses is dropped, otherwise it is bumped to the lowest higher version that is not blocked,
+// chosen from the versions in the module cache and the file:// directories of
+// GOPROXY. Indirect requirements are left to go mod tidy. No fix is returned
+// when nothing changes.
+func (p *Processor) FixModFiles() ([]ModFileFix, error) {
+	src, err := os.ReadFile(filepath.Clean(p.modFilePath))
+	if err != nil {
+		return nil, err
+	}
+
+	modFile, err := modfile.Parse(p.modFilePath, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := p.moduleImports()
+	versionDirs := moduleVersionDirs()
+
+	var changes []RequireChange
+
+	for _, r := range p.Modfile.Require {
+		if r.Indirect {
+			continue
+		}
+
+		if _, blocked := p.checkModule(r.Mod.Path, r.Mod.Version); !blocked {
+			continue
+		}
+
+		if !slices.ContainsFunc(imports, func(pkg string) bool { return isPackageInModule(pkg, r.Mod.Path) }) {
+			if err := modFile.DropRequire(r.Mod.Path); err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, RequireChange{Module: r.Mod.Path, Version: r.Mod.Version})
+
+			continue
+		}
+
+		version, ok := p.lowestAllowedVersion(r.Mod, versionDirs)
+		if !ok {
+			continue
+		}
+
+		if err := modFile.AddRequire(r.Mod.Path, version); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, RequireChange{Module: r.Mod.Path, Version: r.Mod.Version, NewVersion: version})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	modFile.Cleanup()
+
+	fixed, err := modFile.Format()
+	if err != nil {
+		return nil, err
+	}
+
+	return []ModFileFix{{FileName: p.modFilePath, Source: src, Fixed: fixed, Changes: changes}}, nil
+}
+
+// lowestAllowedVersion returns the lowest release of the module higher than
+// its required version that is not blocked.
+func (p *Processor) lowestAllowedVersion(mod module.Version, versionDirs []string) (string, bool) {
+	for _, version := range availableVersions(mod.Path, versionDirs) {
+		if semver.Compare(version, mod.Version) <= 0 || semver.Prerelease(version) != "" {
+			continue
+		}
+
+		if _, blocked := p.checkModule(mod.Path, version); !blocked {
+			return version, true
+		}
+	}
+
+	return "", false
+}
+
+// moduleImports returns the import paths of the Go files of the module,
+// including its test files, and the package paths of its tool directives.
+// Like the go command, nested modules and vendor, testdata and hidden
+// directories are skipped.
+func (p *Processor) moduleImports() []string {
+	var imports []string
+
+	_ = filepath.WalkDir(p.modDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Unreadable directories are skipped.
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != p.modDir && (name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				isModuleDir(path)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil //nolint:nilerr // Files that cannot be parsed are skipped.
+		}
+
+		for _, spec := range file.Imports {
+			if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+				imports = append(imports, importPath)
+			}
+		}
+
+		return nil
+	})
+
+	for _, tool := range p.Modfile.Tool {
+		imports = append(imports, tool.Path)
+	}
+
+	return imports
+}
+
+// isModuleDir returns true if dir has a go.mod file.
+func isModuleDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, goModFilename))
+
+	return err == nil
+}
+
+// availableVersions returns the valid versions of the module found in the
+// download directories, in ascending order. A download directory has the
+// layout of a module proxy, e.g. the cache/download directory of the module
+// cache, where the versions of a module are listed in <module>/@v/list and
+// each version has a <module>/@v/<version>.mod file.
+func availableVersions(modulePath string, versionDirs []string) []string {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil
+	}
+
+	var versions []string
+
+	for _, dir := range versionDirs {
+		versionDir := filepath.Join(dir, escapedPath, "@v")
+
+		if list, err := os.ReadFile(filepath.Join(versionDir, "list")); err == nil {
+			scanner := bufio.NewScanner(bytes.NewReader(list))
+			for scanner.Scan() {
+				// A line may be followed by the timestamp of the version.
+				if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+					versions = append(versions, fields[0])
+				}
+			}
+		}
+
+		modFiles, _ := filepath.Glob(filepath.Join(versionDir, "*.mod"))
+		for _, modFile := range modFiles {
+			if version, err := module.UnescapeVersion(strings.TrimSuffix(filepath.Base(modFile), ".mod")); err == nil {
+				versions = append(versions, version)
+			}
+		}
+	}
+
+	versions = slices.DeleteFunc(versions, func(v string) bool { return !semver.IsValid(v) })
+	semver.Sort(versions)
+
+	return slices.Compact(versions)
+}
+
+// moduleVersionDirs returns the download directory of the module cache and
+// the file:// directories of GOPROXY.
+func moduleVersionDirs() []string {
+	var dirs []string
+
+	if cacheDir := goModCacheDir(); cacheDir != "" {
+		dirs = append(dirs, filepath.Join(cacheDir, "cache", "download"))
+	}
+
+	out, err := exec.Command("go", "env", "GOPROXY").Output() //nolint:noctx // Ack at some point might use os/exec.CommandContext.
+	if err != nil {
+		return dirs
+	}
+
+	for _, proxy := range strings.FieldsFunc(strings.TrimSpace(string(out)), func(r rune) bool { return r == ',' || r == '|' }) {
+		if dir, ok := strings.CutPrefix(proxy, "file://"); ok {
+			dirs = append(dirs, filepath.FromSlash(dir))
+		}
+	}
+
+	return dirs
+}
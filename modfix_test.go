@@ -0,0 +1,139 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorFixModFiles(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "modcache")
+	proxyDir := filepath.Join(dir, "proxy")
+	moduleDir := filepath.Join(dir, "module")
+
+	writeFiles(t, map[string]string{
+		filepath.Join(cacheDir, "cache/download/example.com/bumped/@v/list"):       "v1.0.0\nv1.1.0\nv1.3.0-rc.1\n",
+		filepath.Join(cacheDir, "cache/download/example.com/bumped/@v/v1.2.0.mod"): "module example.com/bumped\n",
+		filepath.Join(proxyDir, "example.com/bumped/@v/v1.4.0.mod"):                "module example.com/bumped\n",
+		filepath.Join(proxyDir, "example.com/stuck/@v/list"):                       "v1.0.0\n",
+		filepath.Join(moduleDir, "go.mod"): `module example.com/service
+
+go 1.25.0
+
+require (
+	example.com/bumped v1.0.0
+	example.com/stuck v1.0.0
+	example.com/unused v1.0.0 // blocked
+	example.com/transitive v1.0.0 // indirect
+)
+`,
+		filepath.Join(moduleDir, "main.go"):          "package main\n\nimport _ \"example.com/bumped/pkg\"\n",
+		filepath.Join(moduleDir, "main_test.go"):     "package main\n\nimport _ \"example.com/stuck\"\n",
+		filepath.Join(moduleDir, "nested/go.mod"):    "module example.com/nested\n",
+		filepath.Join(moduleDir, "nested/nested.go"): "package nested\n\nimport _ \"example.com/unused\"\n",
+	})
+
+	t.Setenv("GOMODCACHE", cacheDir)
+	t.Setenv("GOPROXY", "https://proxy.golang.org,file://"+filepath.ToSlash(proxyDir))
+
+	processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{Module: "example.com/bumped", Version: mustConstraint(t, "< 1.2.0")},
+			{Module: "example.com/stuck"},
+			{Module: "example.com/unused"},
+			{Module: "example.com/transitive"},
+		},
+	}, filepath.Join(moduleDir, "go.mod"))
+	require.NoError(t, err)
+
+	fixes, err := processor.FixModFiles()
+	require.NoError(t, err)
+	require.Len(t, fixes, 1)
+
+	assert.Equal(t, filepath.Join(moduleDir, "go.mod"), fixes[0].FileName)
+	assert.Equal(t, []gomodguard.RequireChange{
+		{Module: "example.com/bumped", Version: "v1.0.0", NewVersion: "v1.2.0"},
+		{Module: "example.com/unused", Version: "v1.0.0"},
+	}, fixes[0].Changes)
+	assert.Equal(t, `module example.com/service
+
+go 1.25.0
+
+require (
+	example.com/bumped v1.2.0
+	example.com/stuck v1.0.0
+	example.com/transitive v1.0.0 // indirect
+)
+`, string(fixes[0].Fixed))
+}
+
+func TestProcessorFixModFilesTool(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "modcache")
+	moduleDir := filepath.Join(dir, "module")
+
+	writeFiles(t, map[string]string{
+		filepath.Join(cacheDir, "cache/download/example.com/linter/@v/list"): "v1.0.0\nv1.1.0\n",
+		filepath.Join(moduleDir, "go.mod"): `module example.com/service
+
+go 1.25.0
+
+tool example.com/linter/cmd/lint
+
+require example.com/linter v1.0.0
+`,
+		filepath.Join(moduleDir, "main.go"): "package main\n",
+	})
+
+	t.Setenv("GOMODCACHE", cacheDir)
+	t.Setenv("GOPROXY", "off")
+
+	processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{Module: "example.com/linter", Version: mustConstraint(t, "< 1.1.0")},
+		},
+	}, filepath.Join(moduleDir, "go.mod"))
+	require.NoError(t, err)
+
+	fixes, err := processor.FixModFiles()
+	require.NoError(t, err)
+	require.Len(t, fixes, 1)
+
+	assert.Equal(t, []gomodguard.RequireChange{
+		{Module: "example.com/linter", Version: "v1.0.0", NewVersion: "v1.1.0"},
+	}, fixes[0].Changes)
+	assert.Equal(t, `module example.com/service
+
+go 1.25.0
+
+tool example.com/linter/cmd/lint
+
+require example.com/linter v1.1.0
+`, string(fixes[0].Fixed))
+}
+
+func TestProcessorFixModFilesUnchanged(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{})
+	require.NoError(t, err)
+
+	fixes, err := processor.FixModFiles()
+	require.NoError(t, err)
+	assert.Empty(t, fixes)
+}
+
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
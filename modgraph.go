@@ -0,0 +1,177 @@
+package gomodguard
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// moduleGraph is the module requirement graph of the main module. It is built
+// offline from the go.mod files in the module cache, so modules that have not
+// been downloaded cannot be followed.
+type moduleGraph struct {
+	requirements map[module.Version][]module.Version
+	// selected is the highest version of each module required in the graph,
+	// which is the version minimal version selection picks.
+	selected map[string]string
+	missing  []module.Version
+}
+
+// loadModuleGraph walks the requirements of the main module through the
+// go.mod files of the module cache in cacheDir, honouring the replace
+// directives of the main module.
+func (p *Processor) loadModuleGraph(cacheDir string) *moduleGraph {
+	graph := &moduleGraph{
+		requirements: make(map[module.Version][]module.Version),
+		selected:     make(map[string]string),
+	}
+
+	queue := make([]module.Version, 0, len(p.Modfile.Require))
+	for _, r := range p.Modfile.Require {
+		queue = append(queue, r.Mod)
+	}
+
+	for len(queue) > 0 {
+		mod := queue[0]
+		queue = queue[1:]
+
+		if _, ok := graph.requirements[mod]; ok {
+			continue
+		}
+
+		if semver.Compare(mod.Version, graph.selected[mod.Path]) > 0 {
+			graph.selected[mod.Path] = mod.Version
+		}
+
+		modFile, err := p.readRequiredGoModFile(cacheDir, mod)
+		if err != nil {
+			graph.requirements[mod] = nil
+			graph.missing = append(graph.missing, mod)
+
+			continue
+		}
+
+		requirements := make([]module.Version, 0, len(modFile.Require))
+		for _, r := range modFile.Require {
+			requirements = append(requirements, r.Mod)
+		}
+
+		graph.requirements[mod] = requirements
+		queue = append(queue, requirements...)
+	}
+
+	// The main module's go.mod lists the selected version of every module it
+	// requires, prefer it over the graph.
+	for _, r := range p.Modfile.Require {
+		graph.selected[r.Mod.Path] = r.Mod.Version
+	}
+
+	return graph
+}
+
+// paths returns the shortest requirement path from root to every module path
+// reachable from it, including root itself.
+func (g *moduleGraph) paths(root module.Version) map[string][]string {
+	paths := map[string][]string{root.Path: {root.Path}}
+	visited := map[module.Version]bool{root: true}
+	queue := []module.Version{root}
+
+	for len(queue) > 0 {
+		mod := queue[0]
+		queue = queue[1:]
+
+		for _, r := range g.requirements[mod] {
+			if visited[r] {
+				continue
+			}
+
+			visited[r] = true
+
+			if _, ok := paths[r.Path]; !ok {
+				paths[r.Path] = append(slices.Clone(paths[mod.Path]), r.Path)
+			}
+
+			queue = append(queue, r)
+		}
+	}
+
+	return paths
+}
+
+// readRequiredGoModFile reads the go.mod file of a required module, applying
+// the replace directives of the main module.
+func (p *Processor) readRequiredGoModFile(cacheDir string, mod module.Version) (*modfile.File, error) {
+	if r := p.replacement(mod); r != nil {
+		if r.New.Version == "" {
+			dir := r.New.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(p.modDir, dir)
+			}
+
+			return readGoModFile(filepath.Join(dir, goModFilename))
+		}
+
+		mod = r.New
+	}
+
+	escapedPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	escapedVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return readGoModFile(filepath.Join(cacheDir, "cache", "download", escapedPath, "@v", escapedVersion+".mod"))
+}
+
+// replacement returns the replace directive of the main module that applies
+// to mod, a directive for the exact version takes precedence over one for
+// all versions.
+func (p *Processor) replacement(mod module.Version) *modfile.Replace {
+	var wildcard *modfile.Replace
+
+	for _, r := range p.Modfile.Replace {
+		if r.Old.Path != mod.Path {
+			continue
+		}
+
+		if r.Old.Version == mod.Version {
+			return r
+		}
+
+		if r.Old.Version == "" {
+			wildcard = r
+		}
+	}
+
+	return wildcard
+}
+
+// readGoModFile reads and leniently parses the go.mod file at path.
+func readGoModFile(path string) (*modfile.File, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return modfile.ParseLax(path, data, nil)
+}
+
+// goModCacheDir returns the module cache directory reported by the go command.
+func goModCacheDir() string {
+	out, err := exec.Command("go", "env", "GOMODCACHE").Output() //nolint:noctx // Ack at some point might use os/exec.CommandContext.
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,142 @@
+package gomodguard
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Override holds the rules for the files of the directories matching a path
+// glob. The rules are merged with the rules of the configuration like the
+// rules of an extending config file, see LoadConfiguration.
+type Override struct {
+	Allowed Allowed      `yaml:"allowed"`
+	Blocked Blocked      `yaml:"blocked"`
+	Remove  RemovedRules `yaml:"remove,omitempty"`
+}
+
+// directoryOverride is a processor for the files of the directories matching
+// an override path glob.
+type directoryOverride struct {
+	pattern   string
+	processor *Processor
+}
+
+// validateOverrides returns an error when an override path glob is malformed.
+func (c *Configuration) validateOverrides() error {
+	for pattern := range c.Overrides {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid override path '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// withOverride returns the configuration with the rules of the override
+// merged in.
+func (c *Configuration) withOverride(o Override) *Configuration {
+	return &Configuration{
+		Allowed:                mergeRules(removeRules(c.Allowed, o.Remove.Allowed, allowedRuleModule), o.Allowed, allowedRuleModule),
+		Blocked:                mergeRules(removeRules(c.Blocked, o.Remove.Blocked, blockedRuleModule), o.Blocked, blockedRuleModule),
+		LocalReplaceDirectives: c.LocalReplaceDirectives,
+		Transitive:             c.Transitive,
+		NotAllowedSeverity:     c.NotAllowedSeverity,
+		ExpiryWarningDays:      c.ExpiryWarningDays,
+		Replace:                c.Replace,
+		GoVersion:              c.GoVersion,
+		Tools:                  c.Tools,
+		Now:                    c.Now,
+	}
+}
+
+// setOverrides creates a processor for each override of the configuration.
+func (p *Processor) setOverrides() error {
+	p.overrides = make([]directoryOverride, 0, len(p.Config.Overrides))
+
+	for pattern, o := range p.Config.Overrides {
+		config := p.Config.withOverride(o)
+		if err := config.InitMatchers(); err != nil {
+			return fmt.Errorf("override '%s': %w", pattern, err)
+		}
+
+		op := &Processor{
+			Config:      config,
+			Modfile:     p.Modfile,
+			modFilePath: p.modFilePath,
+			modDir:      p.modDir,
+		}
+
+		op.SetBlockedModules()
+
+		p.overrides = append(p.overrides, directoryOverride{pattern: pattern, processor: op})
+	}
+
+	slices.SortFunc(p.overrides, func(a, b directoryOverride) int {
+		return cmp.Compare(a.pattern, b.pattern)
+	})
+
+	return nil
+}
+
+// override returns the processor of the override that applies to the file,
+// or nil when none applies. Patterns are matched against the directory of
+// the file relative to the go.mod file and against each of its parents, the
+// override matching the nearest directory wins and the longest pattern breaks
+// ties.
+func (p *Processor) override(filename string) *Processor {
+	if len(p.overrides) == 0 {
+		return nil
+	}
+
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+
+	dir, err := filepath.Rel(p.modDir, filepath.Dir(absPath))
+	if err != nil || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	var (
+		best      *directoryOverride
+		bestDepth = -1
+	)
+
+	for i := range p.overrides {
+		depth := matchDepth(p.overrides[i].pattern, filepath.ToSlash(dir))
+		if depth > bestDepth || (depth == bestDepth && depth >= 0 && len(p.overrides[i].pattern) > len(best.pattern)) {
+			best, bestDepth = &p.overrides[i], depth
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return best.processor
+}
+
+// matchDepth returns the number of path elements of the deepest of dir and
+// its parents that matches pattern, or -1 when none matches.
+func matchDepth(pattern, dir string) int {
+	for {
+		if ok, _ := path.Match(pattern, dir); ok {
+			if dir == "." {
+				return 0
+			}
+
+			return strings.Count(dir, "/") + 1
+		}
+
+		if dir == "." {
+			return -1
+		}
+
+		dir = path.Dir(dir)
+	}
+}
@@ -0,0 +1,85 @@
+package gomodguard_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorOverrides(t *testing.T) {
+	t.Chdir("examples/overrides")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	baseConfig := func() *gomodguard.Configuration {
+		return &gomodguard.Configuration{
+			Allowed: gomodguard.Allowed{
+				{Module: "github.com/gofrs/uuid"},
+				{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		overrides map[string]gomodguard.Override
+		want      []string
+	}{
+		"no overrides": {
+			want: []string{
+				"cmd/tool/main.go:6:2 github.com/mitchellh/go-homedir not-allowed",
+				"example.go:5:2 github.com/mitchellh/go-homedir not-allowed",
+			},
+		},
+		"override of a parent directory": {
+			overrides: map[string]gomodguard.Override{
+				"cmd": {Allowed: gomodguard.Allowed{{Module: "github.com/mitchellh/go-homedir"}}},
+			},
+			want: []string{
+				"example.go:5:2 github.com/mitchellh/go-homedir not-allowed",
+			},
+		},
+		"nearest override wins": {
+			overrides: map[string]gomodguard.Override{
+				"cmd":   {Allowed: gomodguard.Allowed{{Module: "github.com/mitchellh/go-homedir"}}},
+				"cmd/*": {},
+				"*/lib": {Remove: gomodguard.RemovedRules{Allowed: []string{"github.com/gofrs/uuid"}}},
+			},
+			want: []string{
+				"cmd/tool/main.go:6:2 github.com/mitchellh/go-homedir not-allowed",
+				"example.go:5:2 github.com/mitchellh/go-homedir not-allowed",
+				"internal/lib/lib.go:3:8 github.com/gofrs/uuid not-allowed",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := baseConfig()
+			config.Overrides = tt.overrides
+
+			processor, err := gomodguard.NewProcessor(config)
+			require.NoError(t, err)
+
+			issues := processor.ProcessFiles(gomodguard.Find(wd, false, []string{"./..."}))
+
+			got := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, issue.Position.String()+" "+issue.Package+" "+issue.RuleID)
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcessorOverridesInvalidPattern(t *testing.T) {
+	_, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Overrides: map[string]gomodguard.Override{"cmd/[": {}},
+	})
+	require.ErrorContains(t, err, "invalid override path 'cmd/['")
+}
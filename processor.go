@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"golang.org/x/mod/modfile"
 )
@@ -25,8 +27,7 @@ const (
 )
 
 var (
-	blockReasonInBlockedList            = "import of package `%s` is blocked because the module is in the blocked modules list."
-	blockReasonHasLocalReplaceDirective = "import of package `%s` is blocked because the module has a local replace directive."
+	blockReasonInBlockedList = "the module is in the blocked modules list."
 
 	// startsWithVersion is used to test when a string begins with the version identifier of a module,
 	// after having stripped the prefix base module name. IE "github.com/foo/bar/v2/baz" => "v2/baz"
@@ -107,13 +108,76 @@ func (idx *ruleIndex) bestMatch(moduleName string) (string, bool) {
 
 // Configuration of gomodguard allow and block lists.
 type Configuration struct {
-	Allowed                Allowed `yaml:"allowed"`
-	Blocked                Blocked `yaml:"blocked"`
-	LocalReplaceDirectives bool    `yaml:"local_replace_directives"`
+	Allowed Allowed `yaml:"allowed"`
+	Blocked Blocked `yaml:"blocked"`
+	// Deprecated: LocalReplaceDirectives blocks local replace directives
+	// other than those of sibling modules, it is mapped onto the rules of
+	// Replace.Local, use them instead.
+	LocalReplaceDirectives bool `yaml:"local_replace_directives"`
+	Transitive             bool `yaml:"transitive"`
+	// NotAllowedSeverity is the severity of the issues for modules that are
+	// not in the allowed list, it defaults to error.
+	NotAllowedSeverity Severity `yaml:"not_allowed_severity,omitempty"`
+	// ExpiryWarningDays is the number of days before an allowed rule expires,
+	// or a blocked rule waiver ends, that a warning is reported. Zero disables
+	// the warnings, negative values are invalid.
+	ExpiryWarningDays int `yaml:"expiry_warning_days,omitempty"`
+	// Replace holds the rules for the replace directives of go.mod.
+	Replace ReplacePolicy `yaml:"replace,omitempty"`
+	// GoVersion holds the version constraints for the go and toolchain
+	// directives of go.mod.
+	GoVersion GoVersionPolicy `yaml:"go-version,omitempty"`
+	// Tools lists the tool packages that the tool directives of go.mod may
+	// use, when empty any tool of a module that is not blocked may be used.
+	Tools []AllowedTool `yaml:"tools,omitempty"`
+	// Now returns the current time that rule expiry dates are compared with,
+	// it defaults to time.Now.
+	Now func() time.Time `yaml:"-"`
+	// Extends lists the config files the configuration inherits rules from,
+	// see LoadConfiguration.
+	Extends []string `yaml:"extends,omitempty"`
+	// Remove lists inherited rules that do not apply to the configuration.
+	Remove RemovedRules `yaml:"remove,omitempty"`
+	// Overrides holds rules for the files of the directories matching a path
+	// glob, relative to the directory of the go.mod file.
+	Overrides map[string]Override `yaml:"overrides,omitempty"`
 }
 
-// InitMatchers initializes matchers for the configuration rules.
+// InitMatchers initializes matchers for the configuration rules and validates
+// the configured rule IDs.
 func (c *Configuration) InitMatchers() error {
+	if err := c.validateRuleIDs(); err != nil {
+		return err
+	}
+
+	if err := c.validateOverrides(); err != nil {
+		return err
+	}
+
+	if err := c.validateSeverities(); err != nil {
+		return err
+	}
+
+	if err := c.validateRewrites(); err != nil {
+		return err
+	}
+
+	if err := c.validateExpiryWarningDays(); err != nil {
+		return err
+	}
+
+	if c.LocalReplaceDirectives {
+		c.Replace = c.Replace.merge(localReplaceDirectivesPolicy)
+	}
+
+	if err := c.Replace.initMatchers(); err != nil {
+		return err
+	}
+
+	if err := c.initToolMatchers(); err != nil {
+		return err
+	}
+
 	for i := range c.Allowed {
 		m, err := compileMatcher(c.Allowed[i].MatchType, c.Allowed[i].Module)
 		if err != nil {
@@ -135,36 +199,122 @@ func (c *Configuration) InitMatchers() error {
 	return nil
 }
 
+// validateRuleIDs returns an error when a configured rule ID is used by
+// more than one rule or is reserved for a built-in rule.
+func (c *Configuration) validateRuleIDs() error {
+	explicit := make(map[string]bool, len(c.Allowed)+len(c.Blocked))
+
+	register := func(id string, isExplicit bool) error {
+		if isExplicit && isBuiltinRuleID(id) {
+			return fmt.Errorf("rule id '%s' is reserved for a built-in rule", id)
+		}
+
+		if wasExplicit, ok := explicit[id]; ok && (isExplicit || wasExplicit) {
+			return fmt.Errorf("rule id '%s' is used by more than one rule", id)
+		}
+
+		explicit[id] = isExplicit
+
+		return nil
+	}
+
+	for i := range c.Allowed {
+		if err := register(c.Allowed[i].RuleID(), c.Allowed[i].ID != ""); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.Blocked {
+		if err := register(c.Blocked[i].RuleID(), c.Blocked[i].ID != ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Processor processes Go files.
 type Processor struct {
 	Config                    *Configuration
 	Modfile                   *modfile.File
-	blockedModulesFromModFile map[string][]string
+	modFilePath               string
+	modDir                    string
+	blockedModulesFromModFile map[string][]blockedModule
+	blockedIdx                *ruleIndex
+	blockedLookup             map[string]BlockedModule
+	allowedIdx                *ruleIndex
+	allowedLookup             map[string]AllowedModule
+	overrides                 []directoryOverride
+	// matchedRuleIDs holds the IDs of the rules matched by a requirement.
+	matchedRuleIDs map[string]bool
+}
+
+// blockedModule describes why a module required in go.mod is blocked.
+type blockedModule struct {
+	// reason describes why the module is blocked, e.g. "the module is in the blocked modules list."
+	reason          string
+	kind            IssueKind
+	module          string
+	version         string
+	rule            string
+	ruleID          string
+	matchType       MatchType
+	recommendations []string
+	severity        Severity
+	rewrite         map[string]string
+}
+
+// isReplace returns true if the module is blocked because of its replace
+// directive rather than its requirement.
+func (b blockedModule) isReplace() bool {
+	return b.kind == IssueKindBlockedReplace || b.kind == IssueKindNotAllowedReplace
 }
 
 // NewProcessor will create a Processor to lint blocked packages.
 func NewProcessor(config *Configuration) (*Processor, error) {
-	goModFileBytes, err := loadGoModFile()
+	goModFilePath, err := findGoModFile()
 	if err != nil {
 		return nil, fmt.Errorf(errReadingGoModFile, goModFilename, err)
 	}
 
-	modFile, err := modfile.Parse(goModFilename, goModFileBytes, nil)
+	return NewProcessorFromModFile(config, goModFilePath)
+}
+
+// NewProcessorFromModFile will create a Processor to lint blocked packages
+// using the go.mod file at the given path instead of the one of the current
+// working directory.
+func NewProcessorFromModFile(config *Configuration, goModFilePath string) (*Processor, error) {
+	goModFileBytes, err := os.ReadFile(filepath.Clean(goModFilePath))
 	if err != nil {
-		return nil, fmt.Errorf(errParsingGoModFile, goModFilename, err)
+		return nil, fmt.Errorf(errReadingGoModFile, goModFilePath, err)
+	}
+
+	modFile, err := modfile.Parse(goModFilePath, goModFileBytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errParsingGoModFile, goModFilePath, err)
 	}
 
 	if err := config.InitMatchers(); err != nil {
 		return nil, err
 	}
 
+	if absPath, err := filepath.Abs(goModFilePath); err == nil {
+		goModFilePath = absPath
+	}
+
 	p := &Processor{
-		Config:  config,
-		Modfile: modFile,
+		Config:      config,
+		Modfile:     modFile,
+		modFilePath: goModFilePath,
+		modDir:      filepath.Dir(goModFilePath),
 	}
 
 	p.SetBlockedModules()
 
+	if err := p.setOverrides(); err != nil {
+		return nil, err
+	}
+
 	return p, nil
 }
 
@@ -178,6 +328,7 @@ func (p *Processor) ProcessFiles(filenames []string) (issues []Issue) {
 				FileName:   filename,
 				LineNumber: 0,
 				Reason:     fmt.Sprintf("unable to read file, file cannot be linted (%s)", err.Error()),
+				Severity:   SeverityError,
 			})
 
 			continue
@@ -199,125 +350,155 @@ func (p *Processor) ProcessFiles(filenames []string) (issues []Issue) {
 //  1. Exact match — O(1) lookup; wins immediately.
 //  2. Prefix match — longest matching prefix wins.
 //  3. Regex match — evaluated in alphabetical key order; first match wins.
-func (p *Processor) SetBlockedModules() { //nolint:gocognit // Ack this is a long func.
-	blockedModules := make(map[string][]string, len(p.Modfile.Require))
-	currentModuleName := p.Modfile.Module.Mod.Path
+func (p *Processor) SetBlockedModules() {
+	blockedModules := make(map[string][]blockedModule, len(p.Modfile.Require))
 	requiredModules := p.Modfile.Require
 
-	// Build tiered rule indices for blocked and allowed rules.
-	blockedIdx, blockedLookup := buildRuleIndex(
+	p.buildRuleIndices()
+	p.matchedRuleIDs = make(map[string]bool)
+
+	for i := range requiredModules {
+		requiredModuleName := strings.TrimSpace(requiredModules[i].Mod.Path)
+		requiredModuleVersion := strings.TrimSpace(requiredModules[i].Mod.Version)
+
+		p.recordMatchedRules(requiredModuleName)
+
+		if blocked, ok := p.checkModule(requiredModuleName, requiredModuleVersion); ok {
+			blockedModules[requiredModuleName] = append(blockedModules[requiredModuleName], blocked)
+		}
+	}
+
+	// Imports of modules with a blocked replace directive are blocked too,
+	// e.g. to prevent committing local development overrides.
+	for _, r := range p.Modfile.Replace {
+		if blocked, _, ok := p.checkReplace(r); ok {
+			blockedModules[r.Old.Path] = append(blockedModules[r.Old.Path], blocked)
+		}
+	}
+
+	p.blockedModulesFromModFile = blockedModules
+}
+
+// buildRuleIndices builds the tiered rule indices for the blocked and allowed rules.
+func (p *Processor) buildRuleIndices() {
+	p.blockedIdx, p.blockedLookup = buildRuleIndex(
 		p.Config.Blocked,
 		func(r BlockedModule) string    { return r.Module },
 		func(r BlockedModule) MatchType { return r.MatchType },
 		func(r BlockedModule) Matcher   { return r.Matcher },
 	)
-	allowedIdx, allowedLookup := buildRuleIndex(
+	p.allowedIdx, p.allowedLookup = buildRuleIndex(
 		p.Config.Allowed,
 		func(r AllowedModule) string    { return r.Module },
 		func(r AllowedModule) MatchType { return r.MatchType },
 		func(r AllowedModule) Matcher   { return r.Matcher },
 	)
+}
 
-	for i := range requiredModules {
-		requiredModuleName := strings.TrimSpace(requiredModules[i].Mod.Path)
-		requiredModuleVersion := strings.TrimSpace(requiredModules[i].Mod.Version)
-
-		var matchedBlockRule *BlockedModule
-
-		// Check against blocked rules first (exact > longest prefix > first regex)
-		if key, ok := blockedIdx.bestMatch(requiredModuleName); ok {
-			rule := blockedLookup[key] // copy
-			matchedBlockRule = &rule
-		}
-
-		if matchedBlockRule != nil && matchedBlockRule.IsCurrentModuleARecommendation(currentModuleName) {
-			// The current module is a recommended alternative for this blocked module, allowing it.
-			matchedBlockRule = nil
-		}
+// checkModule returns why the module at the given version is blocked by the
+// blocked list or the allowed list, if it is.
+func (p *Processor) checkModule(moduleName, moduleVersion string) (blockedModule, bool) {
+	if blocked, ok := p.checkBlockedList(moduleName, moduleVersion); ok {
+		return blocked, true
+	}
 
-		if matchedBlockRule != nil {
-			isVersBlocked, err := matchedBlockRule.CheckVersion(requiredModuleVersion)
-			if err != nil {
-				// NOTE: Unreachable via real go.mod files; modfile.Parse rejects invalid versions
-				// earlier. Left untested by design as this branch cannot be triggered.
-				blockedModules[requiredModuleName] = append(blockedModules[requiredModuleName],
-					fmt.Sprintf("%s unable to parse version `%s`: %s",
-						blockReasonInBlockedList, requiredModuleVersion, err,
-					),
-				)
+	return p.checkAllowedList(moduleName, moduleVersion)
+}
 
-				continue
-			}
+// checkBlockedList returns why the module at the given version is blocked if
+// it matches a blocked rule (exact > longest prefix > first regex).
+func (p *Processor) checkBlockedList(moduleName, moduleVersion string) (blockedModule, bool) {
+	key, ok := p.blockedIdx.bestMatch(moduleName)
+	if !ok {
+		return blockedModule{}, false
+	}
 
-			if !isVersBlocked {
-				// Doesn't match the blocked version constraint, so we let it pass the block check
-				matchedBlockRule = nil
-			}
-		}
+	rule := p.blockedLookup[key] // copy
 
-		// If it's blocked, record it and move to next
-		if matchedBlockRule != nil {
-			blockedModules[requiredModuleName] = append(blockedModules[requiredModuleName],
-				fmt.Sprintf("%s %s", blockReasonInBlockedList,
-					matchedBlockRule.BlockReason(requiredModuleVersion),
-				),
-			)
+	if rule.IsCurrentModuleARecommendation(p.Modfile.Module.Mod.Path) {
+		// The current module is a recommended alternative for this blocked module, allowing it.
+		return blockedModule{}, false
+	}
 
-			continue
-		}
+	if rule.AllowUntil != nil && !rule.AllowUntil.passed(p.Config.now()) {
+		// The rule is waived until the end of the allow-until day.
+		return blockedModule{}, false
+	}
 
-		// If no allowed list is specified, default mapping is to allow all
-		if len(p.Config.Allowed) == 0 {
-			continue
-		}
+	isVersBlocked, err := rule.CheckVersion(moduleVersion)
+	if err != nil {
+		// NOTE: Unreachable via real go.mod files; modfile.Parse rejects invalid versions
+		// earlier. Left untested by design as this branch cannot be triggered.
+		return rule.blockedModule(key, moduleName, moduleVersion,
+			fmt.Sprintf("%s unable to parse version `%s`: %s",
+				blockReasonInBlockedList, moduleVersion, err,
+			),
+		), true
+	}
 
-		isAllowed := false
+	if !isVersBlocked {
+		// Doesn't match the blocked version constraint, so we let it pass the block check
+		return blockedModule{}, false
+	}
 
-		var matchedButWrongVersion *AllowedModule
+	return rule.blockedModule(key, moduleName, moduleVersion,
+		fmt.Sprintf("%s %s", blockReasonInBlockedList,
+			rule.BlockReason(moduleVersion),
+		),
+	), true
+}
 
-		if key, ok := allowedIdx.bestMatch(requiredModuleName); ok {
-			rule := allowedLookup[key] // copy
+// checkAllowedList returns why the module at the given version is blocked if
+// an allowed list is configured and the module is not in it, or its version
+// does not meet the allowed version constraint.
+func (p *Processor) checkAllowedList(moduleName, moduleVersion string) (blockedModule, bool) {
+	// If no allowed list is specified, default mapping is to allow all
+	if len(p.Config.Allowed) == 0 {
+		return blockedModule{}, false
+	}
 
-			ok, err := rule.CheckVersion(requiredModuleVersion)
+	var matchedButWrongVersion *AllowedModule
 
-			switch {
-			case err != nil:
-				// NOTE: Unreachable via real go.mod files; modfile.Parse rejects invalid versions
-				// earlier. Left untested by design as this branch cannot be triggered.
-				blockedModules[requiredModuleName] = append(blockedModules[requiredModuleName],
-					fmt.Sprintf("import of package `%%s` is blocked because the module version `%s` could not be parsed: %s",
-						requiredModuleVersion, err,
-					),
-				)
+	key, ok := p.allowedIdx.bestMatch(moduleName)
+	if ok {
+		rule := p.allowedLookup[key] // copy
 
-				isAllowed = true // skip the generic "not allowed" message below
-			case ok:
-				isAllowed = true
-			default:
-				matchedButWrongVersion = &rule
-			}
+		if rule.Expires.passed(p.Config.now()) {
+			return rule.expiredModule(key, moduleName, moduleVersion), true
 		}
 
-		if !isAllowed {
-			blockedModules[requiredModuleName] = append(blockedModules[requiredModuleName],
-				fmt.Sprintf("import of package `%%s` is blocked because %s", matchedButWrongVersion.NotAllowedReason(requiredModuleVersion)))
+		ok, err := rule.CheckVersion(moduleVersion)
+
+		switch {
+		case err != nil:
+			// NOTE: Unreachable via real go.mod files; modfile.Parse rejects invalid versions
+			// earlier. Left untested by design as this branch cannot be triggered.
+			return rule.blockedModule(key, moduleName, moduleVersion,
+				fmt.Sprintf("the module version `%s` could not be parsed: %s",
+					moduleVersion, err,
+				),
+			), true
+		case ok:
+			return blockedModule{}, false
+		default:
+			matchedButWrongVersion = &rule
 		}
 	}
 
-	// Blocks local 'replace' directives to prevent committing dev overrides.
-	// Legitimate sibling modules in multi-module repos (sharing the same
-	// module name) are exempt.
-	if p.Config.LocalReplaceDirectives {
-		for _, r := range p.Modfile.Replace {
-			if isBlockedLocalReplace(r) {
-				blockedModules[r.Old.Path] = append(blockedModules[r.Old.Path],
-					blockReasonHasLocalReplaceDirective,
-				)
-			}
-		}
+	reason := matchedButWrongVersion.NotAllowedReason(moduleVersion)
+
+	if matchedButWrongVersion != nil {
+		return matchedButWrongVersion.blockedModule(key, moduleName, moduleVersion, reason), true
 	}
 
-	p.blockedModulesFromModFile = blockedModules
+	return blockedModule{
+		reason:   reason,
+		kind:     IssueKindNotAllowed,
+		ruleID:   NotAllowedRuleID,
+		module:   moduleName,
+		version:  moduleVersion,
+		severity: p.Config.NotAllowedSeverity.orDefault(),
+	}, true
 }
 
 // buildRuleIndex constructs a ruleIndex and a key→rule lookup from any slice of rules.
@@ -355,70 +536,90 @@ func (p *Processor) process(filename string, data []byte) (issues []Issue) {
 			FileName:   filename,
 			LineNumber: 0,
 			Reason:     fmt.Sprintf("invalid syntax, file cannot be linted (%s)", err.Error()),
+			Severity:   SeverityError,
 		})
 
 		return
 	}
 
+	return p.ProcessFile(fileSet, file)
+}
+
+// ProcessFile lints the imports of an already parsed file. The fileSet must
+// be the one the file was parsed with and the file must be parsed with
+// comments for suppression comments to be honoured. The rules of the
+// override for the directory of the file apply, if any.
+func (p *Processor) ProcessFile(fileSet *token.FileSet, file *ast.File) (issues []Issue) {
+	if op := p.override(fileSet.Position(file.Package).Filename); op != nil {
+		return op.ProcessFile(fileSet, file)
+	}
+
+	suppressions := p.parseSuppressions(fileSet, file)
+
 	imports := file.Imports
 	for n := range imports {
 		importedPkg := strings.TrimSpace(strings.Trim(imports[n].Path.Value, "\""))
 
-		blockReasons := p.isBlockedPackageFromModFile(importedPkg)
-		if blockReasons == nil {
-			continue
-		}
+		for _, blocked := range p.isBlockedPackageFromModFile(importedPkg) {
+			issue := p.addError(fileSet, imports[n].Pos(), importedPkg, blocked)
+			if suppressions.suppress(issue.LineNumber, issue) {
+				continue
+			}
 
-		for _, blockReason := range blockReasons {
-			issues = append(issues, p.addError(fileSet, imports[n].Pos(), blockReason))
+			issues = append(issues, issue)
 		}
 	}
 
-	return issues
+	return append(issues, suppressions.issues(fileSet)...)
 }
 
 // addError adds an error for the file and line number for the current token.Pos
-// with the given reason.
-func (p *Processor) addError(fileset *token.FileSet, pos token.Pos, reason string) Issue {
+// of the import of packageName that is blocked.
+func (p *Processor) addError(fileset *token.FileSet, pos token.Pos, packageName string, blocked blockedModule) Issue {
 	position := fileset.Position(pos)
+	rewrite, _ := rewriteImportPath(blocked.rewrite, packageName)
 
 	return Issue{
-		FileName:   position.Filename,
-		LineNumber: position.Line,
-		Position:   position,
-		Reason:     reason,
+		FileName:        position.Filename,
+		LineNumber:      position.Line,
+		Position:        position,
+		Reason:          fmt.Sprintf("import of package `%s` is blocked because %s", packageName, blocked.reason),
+		Kind:            blocked.kind,
+		Package:         packageName,
+		Module:          blocked.module,
+		Version:         blocked.version,
+		Rule:            blocked.rule,
+		RuleID:          blocked.ruleID,
+		MatchType:       blocked.matchType,
+		Recommendations: blocked.recommendations,
+		Severity:        blocked.severity,
+		Rewrite:         rewrite,
 	}
 }
 
-// isBlockedPackageFromModFile returns the block reason if the package is blocked.
-func (p *Processor) isBlockedPackageFromModFile(packageName string) []string {
-	for blockedModuleName, blockReasons := range p.blockedModulesFromModFile {
+// isBlockedPackageFromModFile returns why the package is blocked, or nil
+// if the package is not blocked.
+func (p *Processor) isBlockedPackageFromModFile(packageName string) []blockedModule {
+	for blockedModuleName, blocked := range p.blockedModulesFromModFile {
 		if isPackageInModule(packageName, blockedModuleName) {
-			formattedReasons := make([]string, 0, len(blockReasons))
-
-			for _, blockReason := range blockReasons {
-				formattedReasons = append(formattedReasons, fmt.Sprintf(blockReason, packageName))
-			}
-
-			return formattedReasons
+			return blocked
 		}
 	}
 
 	return nil
 }
 
-// loadGoModFile loads the contents of the go.mod file in the current working directory.
+// findGoModFile returns the path of the go.mod file of the current working directory.
 // It first checks the "GOMOD" environment variable to determine the path of the go.mod file.
-// If the environment variable is not set or the file does not exist, it falls back to reading the go.mod file in the current directory.
+// If the environment variable is not set or the file does not exist, it falls back to the go.mod file in the current directory.
 // If the "GOMOD" environment variable is set to "/dev/null", it returns an error indicating that the current working directory must have a go.mod file.
-// The function returns the contents of the go.mod file as a byte slice and any error encountered during the process.
-func loadGoModFile() ([]byte, error) {
+func findGoModFile() (string, error) {
 	cmd := exec.Command("go", "env", "-json") //nolint:noctx // Ack at some point might use os/exec.CommandContext.
 	stdout, _ := cmd.StdoutPipe()
 	_ = cmd.Start()
 
 	if stdout == nil {
-		return os.ReadFile(filepath.Clean(goModFilename))
+		return goModFilename, nil
 	}
 
 	buf := new(bytes.Buffer)
@@ -428,42 +629,45 @@ func loadGoModFile() ([]byte, error) {
 
 	err := json.Unmarshal(buf.Bytes(), &goEnv)
 	if err != nil {
-		return os.ReadFile(goModFilename)
+		return goModFilename, nil
 	}
 
 	if _, ok := goEnv["GOMOD"]; !ok {
-		return os.ReadFile(goModFilename)
+		return goModFilename, nil
 	}
 
 	if _, err = os.Stat(goEnv["GOMOD"]); os.IsNotExist(err) {
-		return os.ReadFile(goModFilename)
+		return goModFilename, nil
 	}
 
 	if goEnv["GOMOD"] == "/dev/null" || goEnv["GOMOD"] == "NUL" {
-		return nil, errors.New("current working directory must have a go.mod file")
+		return "", errors.New("current working directory must have a go.mod file")
 	}
 
-	return os.ReadFile(goEnv["GOMOD"])
+	return goEnv["GOMOD"], nil
 }
 
-// isBlockedLocalReplace returns true if the replace directive points to a local
-// filesystem path that is not a legitimate sibling module.
-func isBlockedLocalReplace(r *modfile.Replace) bool {
-	if r.New.Path == "" || r.New.Version != "" {
-		return false
+// FindGoModFile walks up from dir and returns the path of the first go.mod
+// file found, which is the go.mod of the module that dir belongs to.
+func FindGoModFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
 	}
 
-	replacePath := r.New.Path
-	if !filepath.IsAbs(replacePath) {
-		wd, err := os.Getwd()
-		if err != nil {
-			wd = "."
+	for {
+		goModFilePath := filepath.Join(dir, goModFilename)
+		if info, err := os.Stat(goModFilePath); err == nil && !info.IsDir() {
+			return goModFilePath, nil
 		}
 
-		replacePath = filepath.Join(wd, replacePath)
-	}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s file found in %s or any parent directory", goModFilename, dir)
+		}
 
-	return !isModuleAtPath(replacePath, r.Old.Path)
+		dir = parent
+	}
 }
 
 // isModuleAtPath returns true if the directory at path contains a go.mod file
@@ -1,7 +1,10 @@
 package gomodguard_test
 
 import (
+	"go/token"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
@@ -42,6 +45,55 @@ func TestProcessorNewProcessorUnknownMatchType(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown match-type")
 }
 
+func TestProcessorNewProcessorDuplicateRuleID(t *testing.T) {
+	_, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{ID: "duplicate", Module: "github.com/foo/bar"},
+		},
+		Blocked: gomodguard.Blocked{
+			{ID: "duplicate", Module: "github.com/foo/baz"},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule id 'duplicate' is used by more than one rule")
+}
+
+func TestProcessorNewProcessorReservedRuleID(t *testing.T) {
+	tests := map[string]struct {
+		config *gomodguard.Configuration
+		want   string
+	}{
+		"allowed rule": {
+			config: &gomodguard.Configuration{
+				Allowed: gomodguard.Allowed{{ID: "not-allowed", Module: "github.com/foo/bar"}},
+			},
+			want: "rule id 'not-allowed' is reserved for a built-in rule",
+		},
+		"blocked rule": {
+			config: &gomodguard.Configuration{
+				Blocked: gomodguard.Blocked{{ID: "go-version/go", Module: "github.com/foo/bar"}},
+			},
+			want: "rule id 'go-version/go' is reserved for a built-in rule",
+		},
+		"replace rule": {
+			config: &gomodguard.Configuration{
+				Replace: gomodguard.ReplacePolicy{
+					Fork: gomodguard.ReplaceRules{Blocked: []gomodguard.ReplaceRule{{ID: "local-replace-directives"}}},
+				},
+			},
+			want: "rule id 'local-replace-directives' of replace rule is reserved for a built-in rule",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := gomodguard.NewProcessor(tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
 func processFiles(t *testing.T, config *gomodguard.Configuration) []string {
 	t.Helper()
 
@@ -80,7 +132,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 				},
 			},
 			wantReasons: []string{
-				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the module is not in the allowed modules list.",
+				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the module is not in the allowed modules list. (not-allowed)",
 			},
 		},
 		"current module is a recommendation - not blocked": {
@@ -108,7 +160,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			},
 			wantReasons: []string{
 				"example.go:3:1 import of package `github.com/Masterminds/semver/v3` is blocked because " +
-					"version `v3.1.0` does not meet the allowed version constraint `>=3.2.0`.",
+					"version `v3.1.0` does not meet the allowed version constraint `>=3.2.0`. (allowed/github.com/Masterminds/semver/v3)",
 			},
 		},
 		"allowed version - passes version constraint": {
@@ -159,7 +211,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the " +
 					"module is in the blocked modules list. `golang.org/x/mod` is a recommended module. " +
-					"exact rule should be selected.",
+					"exact rule should be selected. (blocked/github.com/uudashr/go-module)",
 			},
 			notWantReasons: []string{
 				"regex catch-all should NOT be selected",
@@ -185,7 +237,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			},
 			wantReasons: []string{
 				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the " +
-					"module is in the blocked modules list. prefix rule should be selected.",
+					"module is in the blocked modules list. prefix rule should be selected. (blocked/github.com/uudashr/)",
 			},
 			notWantReasons: []string{
 				"regex catch-all should NOT be selected",
@@ -197,7 +249,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 				LocalReplaceDirectives: true,
 			},
 			wantReasons: []string{
-				"example.go:3:1 import of package `github.com/uudashr/go-module` is blocked because the module has a local replace directive.",
+				"example.go:3:1 import of package `github.com/uudashr/go-module` is blocked because the module has a local replace directive. (local-replace-directives)",
 			},
 		},
 		"local replace directive - not blocked when disabled": {
@@ -221,7 +273,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"example.go:4:1 import of package `github.com/gofrs/uuid` is blocked because the module is in the blocked modules list. " +
 					"`github.com/gofrs/uuid/v5` is a recommended module. " +
-					"testing that a major version module is not blocked by a rule targeting the base module.",
+					"testing that a major version module is not blocked by a rule targeting the base module. (blocked/github.com/gofrs/uuid)",
 			},
 			notWantReasons: []string{"example.go:5:"},
 		},
@@ -239,6 +291,30 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			},
 			wantEmpty: true,
 		},
+		"suppression comments - suppress issues and report unused or reason-less suppressions": {
+			exampleDir: "examples/suppression",
+			config: &gomodguard.Configuration{
+				Blocked: gomodguard.Blocked{
+					{Module: "github.com/uudashr/go-module", Reason: "testing suppression with the wrong rule id."},
+					{Module: "github.com/gofrs/uuid"},
+					{Module: "github.com/mitchellh/go-homedir"},
+				},
+			},
+			wantReasons: []string{
+				"example.go:8:1 suppression comment must give a reason, e.g. `//gomodguard:ignore [rule-id] reason`. " +
+					"(suppression-without-reason)",
+				"example.go:9:1 import of package `github.com/uudashr/go-module` is blocked because the module is in the " +
+					"blocked modules list. testing suppression with the wrong rule id. (blocked/github.com/uudashr/go-module)",
+				"example.go:9:1 suppression comment does not suppress any issue. (unused-suppression)",
+				"example.go:10:1 suppression comment does not suppress any issue. (unused-suppression)",
+			},
+			notWantReasons: []string{
+				"import of package `github.com/gofrs/uuid`",
+				"import of package `github.com/mitchellh/go-homedir`",
+				"example.go:6:1",
+				"example.go:8:1 suppression comment does not suppress any issue.",
+			},
+		},
 		"precedence - longest prefix wins over shorter prefix": {
 			exampleDir: "examples/alloptions",
 			config: &gomodguard.Configuration{
@@ -261,7 +337,7 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 			wantReasons: []string{
 				"blocked_example.go:8:1 import of package `github.com/uudashr/go-module` is blocked because the " +
 					"module is in the blocked modules list. `golang.org/x/mod` is a recommended module. " +
-					"longest prefix should be selected.",
+					"longest prefix should be selected. (blocked/github.com/uudashr/go-module)",
 			},
 			notWantReasons: []string{
 				"short prefix should NOT be selected",
@@ -292,3 +368,127 @@ func TestProcessorProcessFiles(t *testing.T) { //nolint:funlen
 		})
 	}
 }
+
+func TestProcessorProcessFilesUnknownSuppressionRuleID(t *testing.T) {
+	t.Chdir("examples/suppression")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/gofrs/uuid", Reason: "testing unknown rule ids."},
+			{Module: "github.com/mitchellh/go-homedir", Reason: "testing unknown rule ids."},
+			{Module: "github.com/uudashr/go-module", Reason: "testing unknown rule ids."},
+		},
+	})
+	require.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "a.go")
+	src := `package a
+
+import (
+	//gomodguard:ignore [blocked/github.com/gofrs/uuids] typo in the rule id.
+	"github.com/gofrs/uuid"
+	//gomodguard:ignore [blocked/github.com/mitchellh/go-homedir, typo]	one known rule id.
+	"github.com/mitchellh/go-homedir"
+	//gomodguard:ignore [not-alowed] misspelled built-in rule id.
+	"github.com/uudashr/go-module"
+)
+`
+	require.NoError(t, os.WriteFile(filename, []byte(src), 0o600))
+
+	var got []string
+	for _, issue := range processor.ProcessFiles([]string{filename}) {
+		got = append(got, strings.TrimPrefix(issue.String(), filename))
+	}
+
+	assert.Equal(t, []string{
+		":5:1 import of package `github.com/gofrs/uuid` is blocked because the module is in the blocked modules " +
+			"list. testing unknown rule ids. (blocked/github.com/gofrs/uuid)",
+		":9:1 import of package `github.com/uudashr/go-module` is blocked because the module is in the blocked " +
+			"modules list. testing unknown rule ids. (blocked/github.com/uudashr/go-module)",
+		":4:1 suppression comment names unknown rule ids `blocked/github.com/gofrs/uuids`, no issue is suppressed " +
+			"for them. (unknown-suppression-rule)",
+		":6:1 suppression comment names unknown rule ids `typo`, no issue is suppressed for them. " +
+			"(unknown-suppression-rule)",
+		":8:1 suppression comment names unknown rule ids `not-alowed`, no issue is suppressed for them. " +
+			"(unknown-suppression-rule)",
+	}, got)
+}
+
+func TestProcessorIssueMetadata(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{
+				ID:        "allowed-golang",
+				Module:    "golang.org/x",
+				MatchType: gomodguard.PrefixMatch,
+				Version:   mustConstraint(t, ">= 1.0.0"),
+			},
+			{Module: "github.com/gofrs/uuid"},
+		},
+		Blocked: gomodguard.Blocked{
+			{
+				Module:          "github.com/uudashr/go-module",
+				Recommendations: []string{"golang.org/x/mod"},
+				Severity:        gomodguard.SeverityWarning,
+			},
+		},
+		NotAllowedSeverity: gomodguard.SeverityInfo,
+	})
+	require.NoError(t, err)
+
+	issues := processor.ProcessFiles(gomodguard.Find(wd, false, []string{"./..."}))
+
+	got := make(map[string]gomodguard.Issue, len(issues))
+	for _, issue := range issues {
+		issue.Position = token.Position{}
+		got[issue.Package] = issue
+	}
+
+	assert.Equal(t, map[string]gomodguard.Issue{
+		"github.com/mitchellh/go-homedir": {
+			FileName:   "blocked_example.go",
+			LineNumber: 7,
+			Reason:     "import of package `github.com/mitchellh/go-homedir` is blocked because the module is not in the allowed modules list.",
+			Kind:       gomodguard.IssueKindNotAllowed,
+			Package:    "github.com/mitchellh/go-homedir",
+			Module:     "github.com/mitchellh/go-homedir",
+			Version:    "v1.1.0",
+			RuleID:     gomodguard.NotAllowedRuleID,
+			Severity:   gomodguard.SeverityInfo,
+		},
+		"github.com/uudashr/go-module": {
+			FileName:   "blocked_example.go",
+			LineNumber: 8,
+			Reason: "import of package `github.com/uudashr/go-module` is blocked because the module is in the blocked modules list. " +
+				"`golang.org/x/mod` is a recommended module.",
+			Kind:            gomodguard.IssueKindBlocked,
+			Package:         "github.com/uudashr/go-module",
+			Module:          "github.com/uudashr/go-module",
+			Version:         "v0.0.0-20200529023307-c90a4239ad70",
+			Rule:            "github.com/uudashr/go-module",
+			RuleID:          "blocked/github.com/uudashr/go-module",
+			MatchType:       gomodguard.ExactMatch,
+			Recommendations: []string{"golang.org/x/mod"},
+			Severity:        gomodguard.SeverityWarning,
+		},
+		"golang.org/x/mod/modfile": {
+			FileName:   "blocked_example.go",
+			LineNumber: 9,
+			Reason: "import of package `golang.org/x/mod/modfile` is blocked because version `v0.34.0` does not meet the " +
+				"allowed version constraint `>=1.0.0`.",
+			Kind:      gomodguard.IssueKindVersionConstraint,
+			Package:   "golang.org/x/mod/modfile",
+			Module:    "golang.org/x/mod",
+			Version:   "v0.34.0",
+			Rule:      "golang.org/x",
+			RuleID:    "allowed-golang",
+			MatchType: gomodguard.PrefixMatch,
+			Severity:  gomodguard.SeverityError,
+		},
+	}, got)
+}
@@ -0,0 +1,288 @@
+package gomodguard
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ReplaceKind is the kind of a replace directive.
+type ReplaceKind string
+
+const (
+	// ReplaceKindLocal is a replace of a module with a local filesystem path.
+	ReplaceKindLocal ReplaceKind = "local"
+	// ReplaceKindFork is a replace of a module with a different module path.
+	ReplaceKindFork ReplaceKind = "fork"
+	// ReplaceKindVersion is a replace of a module with a version of the same
+	// module path, pinning the version.
+	ReplaceKindVersion ReplaceKind = "version"
+)
+
+const (
+	replaceRuleIDPrefix = "replace"
+
+	// siblingModuleRuleID is the ID of the allowed rule exempting sibling
+	// modules from the local_replace_directives policy.
+	siblingModuleRuleID = LocalReplaceDirectivesRuleID + "/sibling-module"
+)
+
+// localReplaceDirectivesPolicy is the replace policy of the deprecated
+// local_replace_directives option, it blocks every local replace except the
+// replaces of sibling modules.
+var localReplaceDirectivesPolicy = ReplacePolicy{
+	Local: ReplaceRules{
+		Allowed: []ReplaceRule{{ID: siblingModuleRuleID, SiblingModule: true}},
+		Blocked: []ReplaceRule{{ID: LocalReplaceDirectivesRuleID}},
+	},
+}
+
+// ReplacePolicy holds the rules for each kind of replace directive.
+type ReplacePolicy struct {
+	Local   ReplaceRules `yaml:"local,omitempty"`
+	Fork    ReplaceRules `yaml:"fork,omitempty"`
+	Version ReplaceRules `yaml:"version,omitempty"`
+}
+
+// ReplaceRules holds the allowed and blocked rules for a kind of replace
+// directive. A replace matching an allowed rule is never blocked, otherwise a
+// replace matching a blocked rule is blocked, and when the allowed list is not
+// empty a replace matching none of its rules is blocked too.
+type ReplaceRules struct {
+	Allowed []ReplaceRule `yaml:"allowed,omitempty"`
+	Blocked []ReplaceRule `yaml:"blocked,omitempty"`
+}
+
+// ReplaceRule matches replace directives by the module path they replace and
+// the path they replace it with. A rule without Old or New matches any path,
+// so a rule with neither matches every replace of its kind.
+type ReplaceRule struct {
+	// ID identifies the rule in issues and reports. When omitted it is derived
+	// from the kind and the paths, see RuleID.
+	ID string `yaml:"id,omitempty"`
+	// Old is matched against the replaced module path.
+	Old          string    `yaml:"old,omitempty"`
+	OldMatchType MatchType `yaml:"old-match-type,omitempty"`
+	// New is matched against the replacement module path or, for local
+	// replaces, the filesystem path.
+	New          string    `yaml:"new,omitempty"`
+	NewMatchType MatchType `yaml:"new-match-type,omitempty"`
+	// SiblingModule restricts the rule to local replaces whose directory has a
+	// go.mod file declaring the replaced module, i.e. the sibling modules of a
+	// multi-module repository.
+	SiblingModule bool   `yaml:"sibling-module,omitempty"`
+	Reason        string `yaml:"reason,omitempty"`
+	// Severity is the severity of the issues for the replace, it defaults to
+	// error.
+	Severity Severity `yaml:"severity,omitempty"`
+
+	oldMatcher Matcher
+	newMatcher Matcher
+}
+
+// RuleID returns the ID of the rule, or an ID derived from the kind, the list
+// and the paths when none is configured, e.g.
+// "replace/fork/blocked/github.com/foo/bar=>github.com/fork/bar".
+func (r *ReplaceRule) RuleID(kind ReplaceKind, list string) string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	id := fmt.Sprintf("%s/%s/%s", replaceRuleIDPrefix, kind, list)
+
+	switch {
+	case r.Old != "" && r.New != "":
+		return deriveRuleID(id, r.Old+"=>"+r.New)
+	case r.Old != "":
+		return deriveRuleID(id, r.Old)
+	case r.New != "":
+		return deriveRuleID(id, "=>"+r.New)
+	default:
+		return id
+	}
+}
+
+// matches returns true if the rule matches the replace directive of the
+// go.mod file in modDir.
+func (r *ReplaceRule) matches(replace *modfile.Replace, modDir string) bool {
+	return (r.oldMatcher == nil || r.oldMatcher.Match(replace.Old.Path)) &&
+		(r.newMatcher == nil || r.newMatcher.Match(replace.New.Path)) &&
+		(!r.SiblingModule || isSiblingModuleReplace(replace, modDir))
+}
+
+// initMatchers compiles the matchers of the rule.
+func (r *ReplaceRule) initMatchers() error {
+	var err error
+
+	r.oldMatcher, r.newMatcher = nil, nil
+
+	if r.Old != "" {
+		if r.oldMatcher, err = compileMatcher(r.OldMatchType, r.Old); err != nil {
+			return err
+		}
+	}
+
+	if r.New != "" {
+		if r.newMatcher, err = compileMatcher(r.NewMatchType, r.New); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kinds returns the rules of each kind of replace directive.
+func (p *ReplacePolicy) kinds() map[ReplaceKind]*ReplaceRules {
+	return map[ReplaceKind]*ReplaceRules{
+		ReplaceKindLocal:   &p.Local,
+		ReplaceKindFork:    &p.Fork,
+		ReplaceKindVersion: &p.Version,
+	}
+}
+
+// initMatchers compiles the matchers and validates the severities and IDs of
+// the replace rules.
+func (p *ReplacePolicy) initMatchers() error {
+	for kind, rules := range p.kinds() {
+		for list, rs := range map[string][]ReplaceRule{allowedRuleIDPrefix: rules.Allowed, blockedRuleIDPrefix: rules.Blocked} {
+			for i := range rs {
+				if isBuiltinRuleID(rs[i].ID) && !isLocalReplaceDirectivesRule(kind, rs[i].ID) {
+					return fmt.Errorf("rule id '%s' of replace rule is reserved for a built-in rule", rs[i].ID)
+				}
+
+				if !rs[i].Severity.valid() {
+					return fmt.Errorf("invalid severity '%s' for replace rule '%s', %s",
+						rs[i].Severity, rs[i].RuleID(kind, list), severityValues)
+				}
+
+				if err := rs[i].initMatchers(); err != nil {
+					return fmt.Errorf("failed compiling replace matcher for '%s': %w", rs[i].RuleID(kind, list), err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isLocalReplaceDirectivesRule returns true if the rule ID is that of a rule
+// of localReplaceDirectivesPolicy, which the local_replace_directives option
+// merges into the policy.
+func isLocalReplaceDirectivesRule(kind ReplaceKind, id string) bool {
+	return kind == ReplaceKindLocal && (id == LocalReplaceDirectivesRuleID || id == siblingModuleRuleID)
+}
+
+// merge returns the policy with the rules of o merged in, a rule replaces the
+// rule with the same ID of the same list.
+func (p ReplacePolicy) merge(o ReplacePolicy) ReplacePolicy {
+	merged := ReplacePolicy{}
+	overrides := o.kinds()
+
+	for kind, rules := range merged.kinds() {
+		inherited, override := p.kinds()[kind], overrides[kind]
+
+		rules.Allowed = mergeRules(inherited.Allowed, override.Allowed, replaceRuleKey(kind, allowedRuleIDPrefix))
+		rules.Blocked = mergeRules(inherited.Blocked, override.Blocked, replaceRuleKey(kind, blockedRuleIDPrefix))
+	}
+
+	return merged
+}
+
+// replaceRuleKey returns the function keying the replace rules of a list by
+// their ID.
+func replaceRuleKey(kind ReplaceKind, list string) func(ReplaceRule) string {
+	return func(r ReplaceRule) string { return r.RuleID(kind, list) }
+}
+
+// replaceKind returns the kind of the replace directive.
+func replaceKind(r *modfile.Replace) ReplaceKind {
+	switch {
+	case r.New.Version == "":
+		return ReplaceKindLocal
+	case r.New.Path != r.Old.Path:
+		return ReplaceKindFork
+	default:
+		return ReplaceKindVersion
+	}
+}
+
+// isSiblingModuleReplace returns true if the replace directive points to a
+// local directory with a go.mod file declaring the replaced module. Relative
+// paths are resolved against modDir, the directory of the go.mod file.
+func isSiblingModuleReplace(r *modfile.Replace, modDir string) bool {
+	if r.New.Version != "" {
+		return false
+	}
+
+	replacePath := r.New.Path
+	if !filepath.IsAbs(replacePath) {
+		replacePath = filepath.Join(modDir, replacePath)
+	}
+
+	return isModuleAtPath(replacePath, r.Old.Path)
+}
+
+// processReplaces returns an issue positioned at the replace line of every
+// replace directive blocked by the replace policy.
+func (p *Processor) processReplaces() (issues []Issue) {
+	for _, r := range p.Modfile.Replace {
+		blocked, reason, ok := p.checkReplace(r)
+		if !ok {
+			continue
+		}
+
+		issues = append(issues, p.addModFileError(r.Syntax,
+			fmt.Sprintf("replace of module `%s` with `%s` is blocked because %s",
+				r.Old.Path, strings.TrimSpace(r.New.Path+" "+r.New.Version), reason),
+			blocked,
+		))
+	}
+
+	return issues
+}
+
+// checkReplace returns why the replace directive is blocked by the replace
+// policy, if it is. The blocked module describes why imports of the replaced
+// module are blocked and reason why the replace itself is.
+func (p *Processor) checkReplace(r *modfile.Replace) (blocked blockedModule, reason string, ok bool) {
+	kind := replaceKind(r)
+	rules := p.Config.Replace.kinds()[kind]
+	matches := func(rule ReplaceRule) bool { return rule.matches(r, p.modDir) }
+
+	if slices.ContainsFunc(rules.Allowed, matches) {
+		return blockedModule{}, "", false
+	}
+
+	blocked = blockedModule{module: r.Old.Path, version: r.Old.Version, severity: SeverityError}
+
+	if i := slices.IndexFunc(rules.Blocked, matches); i >= 0 {
+		rule := rules.Blocked[i]
+
+		blocked.reason = strings.TrimSpace(fmt.Sprintf("the module has a %s replace directive. %s", kind, rule.Reason))
+		blocked.kind = IssueKindBlockedReplace
+		blocked.ruleID = rule.RuleID(kind, blockedRuleIDPrefix)
+		blocked.severity = rule.Severity.orDefault()
+
+		return blocked, strings.TrimSpace(fmt.Sprintf("the %s replace is in the blocked replaces list. %s",
+			kind, rule.Reason)), true
+	}
+
+	if len(rules.Allowed) > 0 {
+		blocked.reason = fmt.Sprintf("the module has a %s replace directive that is not in the allowed replaces list.", kind)
+		blocked.kind = IssueKindNotAllowedReplace
+		blocked.ruleID = NotAllowedReplaceRuleID(kind)
+
+		return blocked, fmt.Sprintf("the %s replace is not in the allowed replaces list.", kind), true
+	}
+
+	return blockedModule{}, "", false
+}
+
+// NotAllowedReplaceRuleID returns the rule ID of issues for replace
+// directives of a kind that are not in its allowed list.
+func NotAllowedReplaceRuleID(kind ReplaceKind) string {
+	return fmt.Sprintf("%s/%s/%s", replaceRuleIDPrefix, kind, NotAllowedRuleID)
+}
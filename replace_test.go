@@ -0,0 +1,184 @@
+package gomodguard_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorProcessModFileReplaces(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "go.mod"): `module example.com/service
+
+go 1.25.0
+
+replace (
+	example.com/local => ../local
+	example.com/lib => ./lib
+	example.com/fork => github.com/myorg/fork v1.0.0
+	example.com/untrusted => github.com/untrusted/fork v1.0.0
+	example.com/pinned v1.0.0 => example.com/pinned v1.2.3
+)
+`,
+	})
+
+	processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{
+		Replace: gomodguard.ReplacePolicy{
+			Local: gomodguard.ReplaceRules{
+				Allowed: []gomodguard.ReplaceRule{{New: "./", NewMatchType: gomodguard.PrefixMatch}},
+			},
+			Fork: gomodguard.ReplaceRules{
+				Blocked: []gomodguard.ReplaceRule{
+					{New: "github.com/untrusted/", NewMatchType: gomodguard.PrefixMatch, Reason: "Untrusted forks."},
+				},
+			},
+			Version: gomodguard.ReplaceRules{
+				Blocked: []gomodguard.ReplaceRule{
+					{ID: "no-pins", Old: "^example\\.com/", OldMatchType: gomodguard.RegexMatch, Severity: gomodguard.SeverityWarning},
+				},
+			},
+		},
+	}, filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	got := []string{}
+	for _, issue := range processor.ProcessModFile() {
+		got = append(got, fmt.Sprintf("%d %s %s %s: %s", issue.LineNumber, issue.Severity, issue.Kind, issue.RuleID, issue.Reason))
+	}
+
+	assert.Equal(t, []string{
+		"6 error not-allowed-replace replace/local/not-allowed: replace of module `example.com/local` with `../local` " +
+			"is blocked because the local replace is not in the allowed replaces list.",
+		"9 error blocked-replace replace/fork/blocked/=>github.com/untrusted/: replace of module `example.com/untrusted` " +
+			"with `github.com/untrusted/fork v1.0.0` is blocked because the fork replace is in the blocked replaces list. " +
+			"Untrusted forks.",
+		"10 warning blocked-replace no-pins: replace of module `example.com/pinned` with `example.com/pinned v1.2.3` " +
+			"is blocked because the version replace is in the blocked replaces list.",
+	}, got)
+}
+
+func TestProcessorProcessModFileLocalReplaceDirectives(t *testing.T) {
+	tests := map[string]struct {
+		exampleDir string
+		want       []string
+	}{
+		"local replace is blocked": {
+			exampleDir: "examples/localreplace_nomod",
+			want: []string{
+				"go.mod:7:1 replace of module `github.com/uudashr/go-module` with `../doesnotexist` is blocked because the " +
+					"local replace is in the blocked replaces list. (local-replace-directives)",
+			},
+		},
+		"sibling module replace is allowed": {
+			exampleDir: "examples/localreplace",
+			want:       []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(tt.exampleDir)
+
+			processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{LocalReplaceDirectives: true})
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, issue := range processor.ProcessModFile() {
+				got = append(got, issue.String())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcessorProcessModFileAllowedReplaceWins(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "go.mod"): `module example.com/service
+
+go 1.25.0
+
+replace (
+	example.com/tools => ./tools
+	example.com/other => ../other
+)
+`,
+	})
+
+	processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{
+		Replace: gomodguard.ReplacePolicy{
+			Local: gomodguard.ReplaceRules{
+				Allowed: []gomodguard.ReplaceRule{{Old: "example.com/tools"}},
+				Blocked: []gomodguard.ReplaceRule{{ID: "no-local-replaces"}},
+			},
+		},
+	}, filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	got := []string{}
+	for _, issue := range processor.ProcessModFile() {
+		got = append(got, fmt.Sprintf("%d %s: %s", issue.LineNumber, issue.RuleID, issue.Reason))
+	}
+
+	assert.Equal(t, []string{
+		"7 no-local-replaces: replace of module `example.com/other` with `../other` is blocked because the local " +
+			"replace is in the blocked replaces list.",
+	}, got)
+}
+
+func TestProcessorNewProcessorInvalidReplaceRule(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	_, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Replace: gomodguard.ReplacePolicy{
+			Fork: gomodguard.ReplaceRules{
+				Blocked: []gomodguard.ReplaceRule{{Old: "example.com/(", OldMatchType: gomodguard.RegexMatch}},
+			},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed compiling replace matcher for 'replace/fork/blocked/example.com/('")
+}
+
+func TestLoadConfigurationExtendsReplace(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, map[string]string{
+		filepath.Join(dir, "org.yaml"): `replace:
+  local:
+    blocked:
+      - reason: "Local replaces must not be committed."
+  fork:
+    blocked:
+      - id: forks
+        new: github.com/untrusted/
+        new-match-type: prefix
+`,
+		filepath.Join(dir, ".gomodguard.yaml"): `extends:
+  - org.yaml
+replace:
+  fork:
+    blocked:
+      - id: forks
+        new: github.com/other/
+        new-match-type: prefix
+`,
+	})
+
+	config, err := gomodguard.LoadConfiguration(filepath.Join(dir, ".gomodguard.yaml"), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []gomodguard.ReplaceRule{{Reason: "Local replaces must not be committed."}}, config.Replace.Local.Blocked)
+	assert.Equal(t, []gomodguard.ReplaceRule{
+		{ID: "forks", New: "github.com/other/", NewMatchType: gomodguard.PrefixMatch},
+	}, config.Replace.Fork.Blocked)
+}
@@ -0,0 +1,207 @@
+package gomodguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaDescriptions describes the config file fields, keyed by type name and
+// YAML key.
+var schemaDescriptions = map[string]string{
+	"Configuration": "Configuration of gomodguard allow and block lists.",
+	"Configuration.allowed": "Modules that are permitted. When non-empty, any module not matched by " +
+		"an entry is blocked.",
+	"Configuration.blocked": "Modules that are explicitly blocked.",
+	"Configuration.local_replace_directives": "Deprecated, use replace.local instead. Block any module whose " +
+		"replace directive points to a local filesystem path, except sibling modules.",
+	"Configuration.transitive": "Also report blocked modules in the module graph of each direct dependency.",
+	"Configuration.expiry_warning_days": "Number of days before an allowed rule expires, or a blocked rule " +
+		"waiver ends, that a warning is reported. 0 disables the warnings, negative values are invalid.",
+	"Configuration.not_allowed_severity": "Severity of the issues for modules that are not in the allowed list. " +
+		"Defaults to error.",
+	"Configuration.extends": "Config files to inherit rules from.",
+	"Configuration.remove":  "Inherited rules to drop.",
+	"Configuration.overrides": "Rules for the files of the directories matching a path glob, relative to " +
+		"the directory of the go.mod file.",
+	"Configuration.replace": "Rules for the replace directives of go.mod, by kind of replace.",
+	"AllowedModule":         "A module that is permitted.",
+	"AllowedModule.expires": "Last day the rule allows the module, it is not allowed afterwards.",
+	"AllowedModule.id":      "Stable identifier of the rule. Defaults to allowed/<module>.",
+	"AllowedModule.module":  "The module path to match against.",
+	"AllowedModule.version": "Restricts the allowed versions, e.g. >= 1.2.0.",
+	"AllowedModule.severity": "Severity of the issues for versions that do not meet the version constraint. " +
+		"Defaults to error.",
+	"BlockedModule":                 "A module that is blocked.",
+	"BlockedModule.allow-until":     "Last day the rule is waived, the module is blocked afterwards.",
+	"BlockedModule.id":              "Stable identifier of the rule. Defaults to blocked/<module>.",
+	"BlockedModule.module":          "The module path to match against.",
+	"BlockedModule.recommendations": "Alternative modules to suggest in the lint error.",
+	"BlockedModule.reason":          "Human-readable explanation appended to the lint error.",
+	"BlockedModule.version":         "Restricts the blocked versions, e.g. <= 1.2.0.",
+	"BlockedModule.severity":        "Severity of the issues for the module. Defaults to error.",
+	"BlockedModule.rewrite":         "Maps import paths, or their parent paths, to the paths -fix rewrites them to.",
+	"Override":                      "Rules merged with the top-level rules for the files of a directory.",
+	"RemovedRules":                  "Modules of inherited rules to drop.",
+	"RemovedRules.allowed":          "Modules of inherited allowed rules to drop.",
+	"RemovedRules.blocked":          "Modules of inherited blocked rules to drop.",
+	"ReplacePolicy.local":           "Rules for replaces with a local filesystem path.",
+	"ReplacePolicy.fork":            "Rules for replaces with a different module path.",
+	"ReplacePolicy.version":         "Rules for replaces pinning a version of the same module path.",
+	"ReplaceRules.allowed":          "Replaces that are permitted. When not empty, replaces matching no rule are blocked.",
+	"ReplaceRules.blocked":          "Replaces that are blocked.",
+	"ReplaceRule":                   "A rule matching replace directives by their paths, omitted paths match any path.",
+	"ReplaceRule.id":                "Stable identifier of the rule. Defaults to replace/<kind>/<list>/<old>=><new>.",
+	"ReplaceRule.old":               "The replaced module path to match against.",
+	"ReplaceRule.old-match-type":    "How old is matched against the replaced module path. Defaults to exact.",
+	"ReplaceRule.new":               "The replacement module path, or filesystem path, to match against.",
+	"ReplaceRule.new-match-type":    "How new is matched against the replacement path. Defaults to exact.",
+	"ReplaceRule.sibling-module":    "Only match local replaces of sibling modules, whose directory has a go.mod declaring the replaced module.",
+	"ReplaceRule.reason":            "Human-readable explanation appended to the lint error.",
+	"ReplaceRule.severity":          "Severity of the issues for the replace. Defaults to error.",
+	"Configuration.go-version":      "Version constraints for the go and toolchain directives of go.mod.",
+	"GoVersionPolicy.go":            "Constrains the Go language version of the go directive, e.g. >= 1.24.",
+	"GoVersionPolicy.toolchain":     "Constrains the toolchain version, the go directive's version when there is no toolchain directive.",
+	"GoVersionPolicy.reason":        "Human-readable explanation appended to the lint error.",
+	"GoVersionPolicy.severity":      "Severity of the issues for the directives. Defaults to error.",
+	"Configuration.tools":           "Tool packages the tool directives of go.mod may use. When non-empty, other tools are blocked.",
+	"AllowedTool":                   "A tool package that is permitted.",
+	"AllowedTool.path":              "The tool package path to match against.",
+	"AllowedTool.match-type":        "How path is matched against the tool package path. Defaults to exact.",
+}
+
+// schemaRequired lists the required YAML keys by type name.
+var schemaRequired = map[string][]string{
+	"AllowedTool":   {"path"},
+	"AllowedModule": {"module"},
+	"BlockedModule": {"module"},
+}
+
+// JSONSchema returns the JSON Schema of the config file, generated from the
+// Configuration type.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+
+	schema := g.structSchema(reflect.TypeFor[Configuration]())
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = "gomodguard configuration"
+	schema["$defs"] = g.defs
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(schema); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// schemaGenerator generates JSON Schemas from Go types, named struct types
+// other than the root are added to defs and referenced.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeFor[MatchType]():
+		return map[string]any{
+			"type":        "string",
+			"enum":        []MatchType{ExactMatch, PrefixMatch, RegexMatch},
+			"description": "How module is matched against module paths. Defaults to exact.",
+		}
+	case reflect.TypeFor[Severity]():
+		return map[string]any{
+			"type": "string",
+			"enum": []Severity{SeverityError, SeverityWarning, SeverityInfo},
+			"description": "Severity of the issues. Only issues of error severity fail the lint, " +
+				"the others are reported without changing the exit code.",
+		}
+	case reflect.TypeFor[Date]():
+		return map[string]any{
+			"type":        "string",
+			"format":      "date",
+			"description": "A date, e.g. 2026-09-30.",
+		}
+	case reflect.TypeFor[*semver.Constraints]():
+		return map[string]any{
+			"type":        "string",
+			"description": "A semver version constraint, e.g. >= 1.2.0, < 2.0.0.",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // Guards against recursive types.
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the schema of a struct from the YAML keys of its fields.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+
+		property := g.schema(field.Type)
+		if description, ok := schemaDescriptions[t.Name()+"."+key]; ok {
+			property["description"] = description
+		}
+
+		properties[key] = property
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if description, ok := schemaDescriptions[t.Name()]; ok {
+		schema["description"] = description
+	}
+
+	if required, ok := schemaRequired[t.Name()]; ok {
+		schema["required"] = required
+	}
+
+	return schema
+}
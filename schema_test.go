@@ -0,0 +1,95 @@
+package gomodguard_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+const schemaFile = "gomodguard.schema.json"
+
+func TestJSONSchemaIsUpToDate(t *testing.T) {
+	want, err := gomodguard.JSONSchema()
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(want), string(got),
+		"%s is out of date, regenerate it with `gomodguard schema > %s`", schemaFile, schemaFile)
+}
+
+func TestJSONSchemaExamples(t *testing.T) {
+	schema := compileSchema(t)
+
+	configFiles, err := filepath.Glob(filepath.Join("examples", "*", ".gomodguard.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, configFiles)
+
+	policyFiles, err := filepath.Glob(filepath.Join("examples", "*", "policy", "*.yaml"))
+	require.NoError(t, err)
+
+	for _, configFile := range append(configFiles, policyFiles...) {
+		t.Run(configFile, func(t *testing.T) {
+			assert.NoError(t, schema.Validate(loadYAML(t, configFile)))
+		})
+	}
+}
+
+func TestJSONSchemaInvalidConfig(t *testing.T) {
+	schema := compileSchema(t)
+
+	tests := map[string]string{
+		"unknown field":      "blocked:\n  - module: foo\n    recomendations: [bar]\n",
+		"invalid match type": "allowed:\n  - module: foo\n    match-type: regexp\n",
+		"missing module":     "allowed:\n  - match-type: prefix\n",
+		"wrong type":         "local_replace_directives: \"yes\"\n",
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), ".gomodguard.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(config), 0o600))
+
+			assert.Error(t, schema.Validate(loadYAML(t, configFile)))
+		})
+	}
+}
+
+func compileSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+
+	data, err := gomodguard.JSONSchema()
+	require.NoError(t, err)
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource(schemaFile, doc))
+
+	schema, err := compiler.Compile(schemaFile)
+	require.NoError(t, err)
+
+	return schema
+}
+
+func loadYAML(t *testing.T, path string) any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var doc any
+	require.NoError(t, yaml.Load(data, &doc))
+
+	return doc
+}
@@ -0,0 +1,66 @@
+package gomodguard
+
+import "fmt"
+
+// Severity is the severity of the issues reported for a rule. Only issues of
+// error severity fail the lint.
+type Severity string
+
+const (
+	// SeverityError reports issues that fail the lint.
+	SeverityError Severity = "error"
+	// SeverityWarning reports issues that should be addressed but do not fail
+	// the lint.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo reports issues for information only.
+	SeverityInfo Severity = "info"
+)
+
+// orDefault returns the severity, or SeverityError when none is set.
+func (s Severity) orDefault() Severity {
+	if s == "" {
+		return SeverityError
+	}
+
+	return s
+}
+
+// valid returns true if the severity is empty or one of the known severities.
+func (s Severity) valid() bool {
+	switch s {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateSeverities returns an error when a rule or the not allowed severity
+// is not a known severity.
+func (c *Configuration) validateSeverities() error {
+	if !c.NotAllowedSeverity.valid() {
+		return fmt.Errorf("invalid not_allowed_severity '%s', %s", c.NotAllowedSeverity, severityValues)
+	}
+
+	if !c.GoVersion.Severity.valid() {
+		return fmt.Errorf("invalid go-version severity '%s', %s", c.GoVersion.Severity, severityValues)
+	}
+
+	for i := range c.Allowed {
+		if !c.Allowed[i].Severity.valid() {
+			return fmt.Errorf("invalid severity '%s' of allowed rule for '%s', %s",
+				c.Allowed[i].Severity, c.Allowed[i].Module, severityValues)
+		}
+	}
+
+	for i := range c.Blocked {
+		if !c.Blocked[i].Severity.valid() {
+			return fmt.Errorf("invalid severity '%s' of blocked rule for '%s', %s",
+				c.Blocked[i].Severity, c.Blocked[i].Module, severityValues)
+		}
+	}
+
+	return nil
+}
+
+var severityValues = fmt.Sprintf("must be one of %s, %s or %s", SeverityError, SeverityWarning, SeverityInfo)
@@ -0,0 +1,88 @@
+package gomodguard
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/mod/module"
+)
+
+// StarterOptions configures the configuration generated by
+// StarterConfiguration.
+type StarterOptions struct {
+	// CollapsePrefixes replaces the modules sharing an organization, e.g.
+	// github.com/foo, with a single prefix rule.
+	CollapsePrefixes bool
+	// PinVersions constrains the exact rules to the required version or
+	// newer.
+	PinVersions bool
+}
+
+// StarterConfiguration returns a configuration allowing every direct
+// requirement of the go.mod file, to start from when adopting gomodguard.
+// Prefix rules created by collapsing modules have no version constraint.
+func (p *Processor) StarterConfiguration(opts StarterOptions) *Configuration {
+	var requires []module.Version
+
+	for _, r := range p.Modfile.Require {
+		if !r.Indirect {
+			requires = append(requires, r.Mod)
+		}
+	}
+
+	slices.SortFunc(requires, func(a, b module.Version) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	groups := make(map[string]int)
+
+	if opts.CollapsePrefixes {
+		for _, r := range requires {
+			if org, ok := modulePathOrganization(r.Path); ok {
+				groups[org]++
+			}
+		}
+	}
+
+	config := &Configuration{}
+	collapsed := make(map[string]bool)
+
+	for _, r := range requires {
+		if org, ok := modulePathOrganization(r.Path); ok && groups[org] > 1 {
+			if !collapsed[org] {
+				collapsed[org] = true
+
+				config.Allowed = append(config.Allowed, AllowedModule{Module: org + "/", MatchType: PrefixMatch})
+			}
+
+			continue
+		}
+
+		rule := AllowedModule{Module: r.Path}
+
+		if opts.PinVersions {
+			// Versions semver cannot parse are left unpinned.
+			if constraint, err := semver.NewConstraint(">= " + strings.TrimPrefix(r.Version, "v")); err == nil {
+				rule.Version = constraint
+			}
+		}
+
+		config.Allowed = append(config.Allowed, rule)
+	}
+
+	return config
+}
+
+// modulePathOrganization returns the host and first path element of a module
+// path, e.g. github.com/foo for github.com/foo/bar. It returns false for
+// module paths with less than three elements.
+func modulePathOrganization(modulePath string) (string, bool) {
+	elements := strings.SplitN(modulePath, "/", 3)
+	if len(elements) < 3 {
+		return "", false
+	}
+
+	return elements[0] + "/" + elements[1], true
+}
@@ -0,0 +1,85 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorStarterConfiguration(t *testing.T) {
+	goModFile := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goModFile, []byte(`module example.com/service
+
+go 1.25.0
+
+require (
+	github.com/foo/bar v1.2.0
+	github.com/foo/baz v0.0.0-20200529023307-c90a4239ad70
+	github.com/qux/quux v2.0.0+incompatible
+	gopkg.in/yaml.v3 v3.0.1
+	golang.org/x/text v0.14.0 // indirect
+)
+`), 0o600))
+
+	processor, err := gomodguard.NewProcessorFromModFile(&gomodguard.Configuration{}, goModFile)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		opts gomodguard.StarterOptions
+		want []string
+	}{
+		"direct requirements": {
+			want: []string{
+				"exact github.com/foo/bar ",
+				"exact github.com/foo/baz ",
+				"exact github.com/qux/quux ",
+				"exact gopkg.in/yaml.v3 ",
+			},
+		},
+		"pinned versions": {
+			opts: gomodguard.StarterOptions{PinVersions: true},
+			want: []string{
+				"exact github.com/foo/bar >=1.2.0",
+				"exact github.com/foo/baz >=0.0.0-20200529023307-c90a4239ad70",
+				"exact github.com/qux/quux >=2.0.0+incompatible",
+				"exact gopkg.in/yaml.v3 >=3.0.1",
+			},
+		},
+		"collapsed prefixes": {
+			opts: gomodguard.StarterOptions{CollapsePrefixes: true, PinVersions: true},
+			want: []string{
+				"prefix github.com/foo/ ",
+				"exact github.com/qux/quux >=2.0.0+incompatible",
+				"exact gopkg.in/yaml.v3 >=3.0.1",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := processor.StarterConfiguration(tt.opts)
+
+			got := make([]string, 0, len(config.Allowed))
+			for _, r := range config.Allowed {
+				version := ""
+				if r.Version != nil {
+					version = r.Version.String()
+				}
+
+				matchType := r.MatchType
+				if matchType == "" {
+					matchType = gomodguard.ExactMatch
+				}
+
+				got = append(got, string(matchType)+" "+r.Module+" "+version)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
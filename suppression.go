@@ -0,0 +1,223 @@
+package gomodguard
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+const (
+	// UnusedSuppressionRuleID is the rule ID of issues for suppression comments
+	// that do not suppress any issue.
+	UnusedSuppressionRuleID = "unused-suppression"
+	// SuppressionWithoutReasonRuleID is the rule ID of issues for suppression
+	// comments that do not give a reason.
+	SuppressionWithoutReasonRuleID = "suppression-without-reason"
+	// UnknownSuppressionRuleID is the rule ID of issues for suppression
+	// comments naming rule IDs that are not the ID of any rule.
+	UnknownSuppressionRuleID = "unknown-suppression-rule"
+
+	suppressionDirective = "//gomodguard:ignore"
+
+	reasonUnusedSuppression        = "suppression comment does not suppress any issue."
+	reasonSuppressionWithoutReason = "suppression comment must give a reason, e.g. `//gomodguard:ignore [rule-id] reason`."
+	reasonUnknownSuppressionRule   = "suppression comment names unknown rule ids %s, no issue is suppressed for them."
+)
+
+// suppression is a `//gomodguard:ignore [rule-id] reason` comment.
+type suppression struct {
+	pos     token.Pos
+	line    int
+	ruleIDs []string
+	// unknownRuleIDs are the named rule IDs that are not the ID of any rule.
+	unknownRuleIDs []string
+	reason         string
+	used           bool
+}
+
+// suppressions holds the suppression comments of a file by line.
+type suppressions struct {
+	byLine map[int]*suppression
+	// trailingLines are the lines an import spec ends on, a suppression on
+	// such a line applies to that import only.
+	trailingLines map[int]bool
+}
+
+// parseSuppressions finds the suppression comments of the file. A text
+// starting with brackets names the comma separated rule IDs the suppression
+// applies to, and the text after the closing bracket is the reason. Named IDs
+// that are not the ID of a configured or built-in rule suppress nothing.
+// Without brackets the whole text is the reason and the suppression applies
+// to every rule.
+func (p *Processor) parseSuppressions(fileSet *token.FileSet, file *ast.File) *suppressions {
+	s := &suppressions{
+		byLine:        make(map[int]*suppression),
+		trailingLines: make(map[int]bool, len(file.Imports)),
+	}
+
+	for _, spec := range file.Imports {
+		s.trailingLines[fileSet.Position(spec.End()).Line] = true
+	}
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			text, ok := strings.CutPrefix(c.Text, suppressionDirective)
+			if !ok || (text != "" && text[0] != ' ' && text[0] != '\t') {
+				continue
+			}
+
+			sup := &suppression{
+				pos:    c.Pos(),
+				line:   fileSet.Position(c.Pos()).Line,
+				reason: strings.TrimSpace(text),
+			}
+
+			if list, ok := strings.CutPrefix(sup.reason, "["); ok {
+				list, rest, _ := strings.Cut(list, "]")
+
+				for _, id := range strings.Split(list, ",") {
+					switch id = strings.TrimSpace(id); {
+					case id == "":
+					case p.isRuleID(id):
+						sup.ruleIDs = append(sup.ruleIDs, id)
+					default:
+						sup.unknownRuleIDs = append(sup.unknownRuleIDs, id)
+					}
+				}
+
+				sup.reason = strings.TrimSpace(rest)
+			}
+
+			s.byLine[sup.line] = sup
+		}
+	}
+
+	return s
+}
+
+// suppress returns true if the issue reported on the import spec at line
+// is suppressed by a comment on the same line or on the line directly above.
+func (s *suppressions) suppress(line int, issue Issue) bool {
+	sup, ok := s.byLine[line]
+	if !ok && !s.trailingLines[line-1] {
+		sup, ok = s.byLine[line-1]
+	}
+
+	if !ok || (sup.namesRules() && !slices.Contains(sup.ruleIDs, issue.RuleID)) {
+		return false
+	}
+
+	sup.used = true
+
+	return true
+}
+
+// namesRules returns true if the suppression names the rules it applies to,
+// known or not.
+func (s *suppression) namesRules() bool {
+	return len(s.ruleIDs) > 0 || len(s.unknownRuleIDs) > 0
+}
+
+// issues returns the issues for suppression comments that are unused, do not
+// give a reason or name unknown rule IDs. A suppression naming only unknown
+// rule IDs is not reported as unused too.
+func (s *suppressions) issues(fileSet *token.FileSet) []Issue {
+	sups := make([]*suppression, 0, len(s.byLine))
+	for _, sup := range s.byLine {
+		sups = append(sups, sup)
+	}
+
+	slices.SortFunc(sups, func(a, b *suppression) int { return a.line - b.line })
+
+	var issues []Issue
+
+	for _, sup := range sups {
+		position := fileSet.Position(sup.pos)
+
+		if len(sup.unknownRuleIDs) > 0 {
+			ids := make([]string, 0, len(sup.unknownRuleIDs))
+			for _, id := range sup.unknownRuleIDs {
+				ids = append(ids, fmt.Sprintf("`%s`", id))
+			}
+
+			issues = append(issues, Issue{
+				FileName:   position.Filename,
+				LineNumber: position.Line,
+				Position:   position,
+				Reason:     fmt.Sprintf(reasonUnknownSuppressionRule, strings.Join(ids, ", ")),
+				Kind:       IssueKindUnknownSuppressionRule,
+				RuleID:     UnknownSuppressionRuleID,
+				Severity:   SeverityError,
+			})
+		}
+
+		if !sup.used && (len(sup.ruleIDs) > 0 || !sup.namesRules()) {
+			issues = append(issues, Issue{
+				FileName:   position.Filename,
+				LineNumber: position.Line,
+				Position:   position,
+				Reason:     reasonUnusedSuppression,
+				Kind:       IssueKindUnusedSuppression,
+				RuleID:     UnusedSuppressionRuleID,
+				Severity:   SeverityError,
+			})
+		}
+
+		if sup.reason == "" {
+			issues = append(issues, Issue{
+				FileName:   position.Filename,
+				LineNumber: position.Line,
+				Position:   position,
+				Reason:     reasonSuppressionWithoutReason,
+				Kind:       IssueKindSuppressionWithoutReason,
+				RuleID:     SuppressionWithoutReasonRuleID,
+				Severity:   SeverityError,
+			})
+		}
+	}
+
+	return issues
+}
+
+// isRuleID returns true if id is the ID of a configured or built-in rule.
+func (p *Processor) isRuleID(id string) bool {
+	switch id {
+	case NotAllowedRuleID, LocalReplaceDirectivesRuleID, NotAllowedToolRuleID, GoDirectiveRuleID,
+		ToolchainDirectiveRuleID, IncompleteModuleGraphRuleID:
+		return true
+	}
+
+	for kind, rules := range p.Config.Replace.kinds() {
+		if id == NotAllowedReplaceRuleID(kind) {
+			return true
+		}
+
+		for i := range rules.Allowed {
+			if rules.Allowed[i].RuleID(kind, allowedRuleIDPrefix) == id {
+				return true
+			}
+		}
+
+		for i := range rules.Blocked {
+			if rules.Blocked[i].RuleID(kind, blockedRuleIDPrefix) == id {
+				return true
+			}
+		}
+	}
+
+	for i := range p.Config.Allowed {
+		if p.Config.Allowed[i].RuleID() == id {
+			return true
+		}
+	}
+
+	for i := range p.Config.Blocked {
+		if p.Config.Blocked[i].RuleID() == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package gomodguard
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/mod/modfile"
+)
+
+// NotAllowedToolRuleID is the rule ID of issues for tools that are not in the
+// allowed tools list.
+const NotAllowedToolRuleID = "not-allowed-tool"
+
+// AllowedTool is a single entry in the allowed tools list.
+type AllowedTool struct {
+	// Path is matched against the package path of the tool directive.
+	Path      string    `yaml:"path"`
+	MatchType MatchType `yaml:"match-type,omitempty"`
+	Matcher   Matcher   `yaml:"-"`
+}
+
+// initToolMatchers compiles the matchers of the allowed tools.
+func (c *Configuration) initToolMatchers() error {
+	for i := range c.Tools {
+		m, err := compileMatcher(c.Tools[i].MatchType, c.Tools[i].Path)
+		if err != nil {
+			return fmt.Errorf("failed compiling tool matcher for '%s': %w", c.Tools[i].Path, err)
+		}
+
+		c.Tools[i].Matcher = m
+	}
+
+	return nil
+}
+
+// processTools returns an issue positioned at the tool line of every tool
+// directive whose package belongs to a blocked requirement, and of every tool
+// that is not in the allowed tools list when one is configured. Tools of the
+// main module are only checked against the allowed tools list.
+func (p *Processor) processTools() (issues []Issue) {
+	for _, tool := range p.Modfile.Tool {
+		if len(p.Config.Tools) > 0 && !slices.ContainsFunc(p.Config.Tools, func(t AllowedTool) bool {
+			return t.Matcher != nil && t.Matcher.Match(tool.Path)
+		}) {
+			issue := p.addModFileError(tool.Syntax,
+				fmt.Sprintf("tool `%s` is blocked because the tool is not in the allowed tools list.", tool.Path),
+				blockedModule{kind: IssueKindNotAllowedTool, ruleID: NotAllowedToolRuleID, severity: SeverityError},
+			)
+			issue.Package = tool.Path
+
+			issues = append(issues, issue)
+		}
+
+		require := p.toolRequire(tool)
+		if require == nil {
+			continue
+		}
+
+		for _, blocked := range p.blockedModulesFromModFile[require.Mod.Path] {
+			if blocked.isReplace() {
+				continue
+			}
+
+			issue := p.addModFileError(tool.Syntax,
+				fmt.Sprintf("tool `%s` is blocked because %s", tool.Path, blocked.reason),
+				blocked,
+			)
+			issue.Package = tool.Path
+
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// toolRequire returns the requirement of the module the tool package belongs
+// to, the longest matching module path wins, or nil when the tool belongs to
+// no required module.
+func (p *Processor) toolRequire(tool *modfile.Tool) *modfile.Require {
+	var owner *modfile.Require
+
+	for _, r := range p.Modfile.Require {
+		if isPackageInModule(tool.Path, r.Mod.Path) && (owner == nil || len(r.Mod.Path) > len(owner.Mod.Path)) {
+			owner = r
+		}
+	}
+
+	return owner
+}
@@ -0,0 +1,93 @@
+package gomodguard_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorProcessModFileTools(t *testing.T) {
+	goMod := `module example.com/service
+
+go 1.25.0
+
+tool (
+	example.com/service/cmd/gen
+	golang.org/x/tools/cmd/stringer
+	github.com/badcompany/linter/cmd/lint
+	github.com/foo/bar/v2/cmd/bar
+)
+
+require (
+	github.com/badcompany/linter v1.0.0
+	github.com/foo/bar/v2 v2.1.0
+	golang.org/x/tools v0.30.0 // indirect
+)
+`
+
+	tests := map[string]struct {
+		config *gomodguard.Configuration
+		want   []string
+	}{
+		"blocked module": {
+			config: &gomodguard.Configuration{
+				Blocked: gomodguard.Blocked{
+					{Module: "github.com/badcompany/", MatchType: gomodguard.PrefixMatch},
+					{Module: "github.com/foo/bar/v2", Version: mustConstraint(t, "< 2.0.0")},
+				},
+			},
+			want: []string{
+				"8 blocked blocked/github.com/badcompany/ github.com/badcompany/linter: tool " +
+					"`github.com/badcompany/linter/cmd/lint` is blocked because the module is in the blocked modules list. ",
+			},
+		},
+		"allowed tools list": {
+			config: &gomodguard.Configuration{
+				Tools: []gomodguard.AllowedTool{
+					{Path: "golang.org/x/tools/cmd/", MatchType: gomodguard.PrefixMatch},
+					{Path: "example.com/service/cmd/gen"},
+				},
+			},
+			want: []string{
+				"8 not-allowed-tool not-allowed-tool : tool `github.com/badcompany/linter/cmd/lint` is blocked because " +
+					"the tool is not in the allowed tools list.",
+				"9 not-allowed-tool not-allowed-tool : tool `github.com/foo/bar/v2/cmd/bar` is blocked because the tool " +
+					"is not in the allowed tools list.",
+			},
+		},
+		"allowed modules list": {
+			config: &gomodguard.Configuration{
+				Allowed: gomodguard.Allowed{
+					{Module: "golang.org/x/tools"},
+					{Module: "github.com/foo/bar/v2"},
+				},
+			},
+			want: []string{
+				"8 not-allowed not-allowed github.com/badcompany/linter: tool `github.com/badcompany/linter/cmd/lint` " +
+					"is blocked because the module is not in the allowed modules list.",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			goModFilePath := filepath.Join(t.TempDir(), "go.mod")
+			writeFiles(t, map[string]string{goModFilePath: goMod})
+
+			processor, err := gomodguard.NewProcessorFromModFile(tt.config, goModFilePath)
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, issue := range processor.ProcessModFile() {
+				got = append(got, fmt.Sprintf("%d %s %s %s: %s", issue.LineNumber, issue.Kind, issue.RuleID, issue.Module, issue.Reason))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
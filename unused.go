@@ -0,0 +1,45 @@
+package gomodguard
+
+// UnusedRule is a rule of the configuration that matches no requirement of
+// the go.mod file.
+type UnusedRule struct {
+	// List is the list of the rule, allowed or blocked.
+	List   string
+	Module string
+	RuleID string
+}
+
+// recordMatchedRules records the allowed and blocked rules that win for the
+// required module, whatever its version.
+func (p *Processor) recordMatchedRules(moduleName string) {
+	if key, ok := p.blockedIdx.bestMatch(moduleName); ok {
+		rule := p.blockedLookup[key]
+		p.matchedRuleIDs[rule.RuleID()] = true
+	}
+
+	if key, ok := p.allowedIdx.bestMatch(moduleName); ok {
+		rule := p.allowedLookup[key]
+		p.matchedRuleIDs[rule.RuleID()] = true
+	}
+}
+
+// UnusedRules returns the top-level rules of the configuration that are not
+// the best match of any module required in go.mod, in configuration order.
+// Such rules are likely stale and can be pruned. A rule matching a required
+// module is used even when its version constraint excludes the required
+// version.
+func (p *Processor) UnusedRules() (unused []UnusedRule) {
+	for i := range p.Config.Blocked {
+		if ruleID := p.Config.Blocked[i].RuleID(); !p.matchedRuleIDs[ruleID] {
+			unused = append(unused, UnusedRule{List: "blocked", Module: p.Config.Blocked[i].Module, RuleID: ruleID})
+		}
+	}
+
+	for i := range p.Config.Allowed {
+		if ruleID := p.Config.Allowed[i].RuleID(); !p.matchedRuleIDs[ruleID] {
+			unused = append(unused, UnusedRule{List: "allowed", Module: p.Config.Allowed[i].Module, RuleID: ruleID})
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,51 @@
+package gomodguard_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestProcessorUnusedRules(t *testing.T) {
+	t.Chdir("examples/alloptions")
+
+	processor, err := gomodguard.NewProcessor(&gomodguard.Configuration{
+		Allowed: gomodguard.Allowed{
+			{Module: "golang.org", MatchType: gomodguard.PrefixMatch},
+			{Module: "golang.org/x", MatchType: gomodguard.PrefixMatch},
+			{Module: "gopkg.in/yaml.v3"},
+			{Module: `^github\.com/gofrs/.*$`, MatchType: gomodguard.RegexMatch},
+		},
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/mitchellh/go-homedir", Version: mustConstraint(t, "> 1.1.0")},
+			{Module: "github.com/uudashr/go-module", ID: "no-go-module"},
+			{Module: "github.com/foo/bar"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []gomodguard.UnusedRule{
+		{List: "blocked", Module: "github.com/foo/bar", RuleID: "blocked/github.com/foo/bar"},
+		{List: "allowed", Module: "golang.org", RuleID: "allowed/golang.org"},
+		{List: "allowed", Module: "gopkg.in/yaml.v3", RuleID: "allowed/gopkg.in/yaml.v3"},
+	}, processor.UnusedRules())
+}
+
+func TestWorkspaceUnusedRules(t *testing.T) {
+	t.Chdir("examples/workspace")
+
+	workspace, err := gomodguard.NewWorkspace(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{Module: "github.com/mitchellh/go-homedir", Version: mustConstraint(t, "< 1.1.0")},
+			{Module: "github.com/gofrs/uuid"},
+		},
+	}, "go.work")
+	require.NoError(t, err)
+
+	assert.Equal(t, []gomodguard.UnusedRule{
+		{List: "blocked", Module: "github.com/gofrs/uuid", RuleID: "blocked/github.com/gofrs/uuid"},
+	}, workspace.UnusedRules())
+}
@@ -0,0 +1,562 @@
+package gomodguard
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+	"golang.org/x/mod/module"
+)
+
+// ProblemLevel is the level of a problem found in a config file.
+type ProblemLevel string
+
+const (
+	// ProblemError is the level of problems that make the config file invalid.
+	ProblemError ProblemLevel = "error"
+	// ProblemWarning is the level of problems that are likely mistakes.
+	ProblemWarning ProblemLevel = "warning"
+)
+
+// syntaxErrorPosition finds the position in the message of a YAML syntax
+// error, the last position is where the error occurred.
+var syntaxErrorPosition = regexp.MustCompile(`line (\d+), column (\d+)`)
+
+// ConfigProblem is an error or warning found in a config file.
+type ConfigProblem struct {
+	FileName string
+	Line     int
+	Column   int
+	Level    ProblemLevel
+	Message  string
+}
+
+// String returns the problem as "file:line:column: level: message".
+func (p ConfigProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", p.FileName, p.Level, p.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s: %s", p.FileName, p.Line, p.Column, p.Level, p.Message)
+}
+
+// configRule is a rule of a config file and the position of its fields.
+type configRule struct {
+	list      string
+	module    string
+	matchType MatchType
+	id        string
+	node      *yaml.Node
+	idNode    *yaml.Node
+	// moduleNode and matchTypeNode are the value nodes of the fields, or
+	// the rule node when the field is not set.
+	moduleNode    *yaml.Node
+	matchTypeNode *yaml.Node
+}
+
+// configValidator collects the problems of a config file.
+type configValidator struct {
+	fileName string
+	problems []ConfigProblem
+}
+
+// ValidateConfiguration strictly decodes the config file at configPath, rejecting
+// unknown fields, and returns the problems found, sorted by position. Besides
+// errors it warns about likely mistakes: duplicate rules for a module, modules
+// in both the allowed and blocked lists, prefix rules shadowed by longer
+// prefix rules and regexes that can never match a module path.
+//
+// The extended config files are loaded, with policyDir as for
+// LoadConfiguration, but not validated themselves.
+func ValidateConfiguration(configPath, policyDir string) []ConfigProblem {
+	v := &configValidator{fileName: configPath}
+
+	data, err := os.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		v.add(nil, ProblemError, fmt.Errorf(errReadingConfigFile, err).Error())
+		return v.problems
+	}
+
+	var root yaml.Node
+
+	if err := yaml.Load(data, &root); err != nil {
+		v.addSyntaxError(err)
+		return v.problems
+	}
+
+	var config Configuration
+
+	if err := yaml.Load(data, &config, yaml.WithKnownFields()); err != nil {
+		var loadErrs *yaml.LoadErrors
+		if !errors.As(err, &loadErrs) {
+			v.add(nil, ProblemError, err.Error())
+			return v.problems
+		}
+
+		for _, loadErr := range loadErrs.Errors {
+			v.problems = append(v.problems, ConfigProblem{
+				FileName: configPath,
+				Line:     loadErr.Line,
+				Column:   loadErr.Column,
+				Level:    ProblemError,
+				Message:  loadErr.Err.Error(),
+			})
+		}
+	}
+
+	doc := &root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+
+	if doc.Kind == yaml.MappingNode {
+		v.checkSeverity(mappingValue(doc, "not_allowed_severity"))
+		v.checkExpiryWarningDays(mappingValue(doc, "expiry_warning_days"))
+		v.checkRules(mappingValue(doc, "allowed"), mappingValue(doc, "blocked"), true)
+		v.checkOverrides(mappingValue(doc, "overrides"))
+		v.checkReplace(mappingValue(doc, "replace"))
+
+		if goVersion := mappingValue(doc, "go-version"); goVersion != nil && goVersion.Kind == yaml.MappingNode {
+			v.checkSeverity(mappingValue(goVersion, "severity"))
+		}
+
+		v.checkTools(mappingValue(doc, "tools"))
+
+		if key := mappingKey(doc, "local_replace_directives"); key != nil {
+			v.add(key, ProblemWarning, "local_replace_directives is deprecated, block local replaces with "+
+				"replace.local.blocked instead")
+		}
+	}
+
+	if !slices.ContainsFunc(v.problems, func(p ConfigProblem) bool { return p.Level == ProblemError }) {
+		if _, err := LoadConfiguration(configPath, policyDir); err != nil {
+			v.add(mappingKey(doc, "extends"), ProblemError, err.Error())
+		}
+	}
+
+	slices.SortStableFunc(v.problems, func(a, b ConfigProblem) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	return v.problems
+}
+
+// add records a problem at the position of node, or for the whole file when
+// node is nil.
+func (v *configValidator) add(node *yaml.Node, level ProblemLevel, message string) {
+	problem := ConfigProblem{FileName: v.fileName, Level: level, Message: message}
+	if node != nil {
+		problem.Line = node.Line
+		problem.Column = node.Column
+	}
+
+	v.problems = append(v.problems, problem)
+}
+
+// addSyntaxError records a YAML syntax error at the position in its message.
+func (v *configValidator) addSyntaxError(err error) {
+	problem := ConfigProblem{FileName: v.fileName, Level: ProblemError, Message: err.Error()}
+
+	if matches := syntaxErrorPosition.FindAllStringSubmatch(err.Error(), -1); len(matches) > 0 {
+		last := matches[len(matches)-1]
+		problem.Line, _ = strconv.Atoi(last[1])
+		problem.Column, _ = strconv.Atoi(last[2])
+	}
+
+	v.problems = append(v.problems, problem)
+}
+
+// checkOverrides checks the path globs and rules of the overrides.
+func (v *configValidator) checkOverrides(overrides *yaml.Node) {
+	if overrides == nil || overrides.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(overrides.Content); i += 2 {
+		key, value := overrides.Content[i], overrides.Content[i+1]
+
+		if _, err := path.Match(key.Value, ""); err != nil {
+			v.add(key, ProblemError, fmt.Sprintf("invalid override path `%s`: %s", key.Value, err))
+		}
+
+		if value.Kind == yaml.MappingNode {
+			v.checkRules(mappingValue(value, "allowed"), mappingValue(value, "blocked"), false)
+		}
+	}
+}
+
+// checkReplace checks the severities, match types and regexes of the replace
+// rules of each kind of replace directive.
+func (v *configValidator) checkReplace(replace *yaml.Node) {
+	if replace == nil || replace.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 1; i < len(replace.Content); i += 2 {
+		if replace.Content[i].Kind != yaml.MappingNode {
+			continue
+		}
+
+		for _, list := range []string{"allowed", "blocked"} {
+			rules := mappingValue(replace.Content[i], list)
+			if rules == nil || rules.Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for _, entry := range rules.Content {
+				if entry.Kind != yaml.MappingNode {
+					continue
+				}
+
+				v.checkSeverity(mappingValue(entry, "severity"))
+				v.checkPathMatchType(mappingValue(entry, "old"), mappingValue(entry, "old-match-type"))
+				v.checkPathMatchType(mappingValue(entry, "new"), mappingValue(entry, "new-match-type"))
+			}
+		}
+	}
+}
+
+// checkTools checks the match types and regexes of the allowed tools.
+func (v *configValidator) checkTools(tools *yaml.Node) {
+	if tools == nil || tools.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for _, entry := range tools.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if mappingValue(entry, "path") == nil {
+			v.add(entry, ProblemError, "tools rule has no path")
+			continue
+		}
+
+		v.checkPathMatchType(mappingValue(entry, "path"), mappingValue(entry, "match-type"))
+	}
+}
+
+// checkPathMatchType reports an invalid match type of a replace or tool rule
+// path, or a regex path that does not compile.
+func (v *configValidator) checkPathMatchType(pathNode, matchTypeNode *yaml.Node) {
+	if matchTypeNode == nil {
+		return
+	}
+
+	switch MatchType(matchTypeNode.Value).orDefault() {
+	case ExactMatch, PrefixMatch:
+	case RegexMatch:
+		if pathNode == nil {
+			return
+		}
+
+		if _, err := regexp.Compile(pathNode.Value); err != nil {
+			v.add(pathNode, ProblemError, fmt.Sprintf("invalid regex `%s`: %s", pathNode.Value, err))
+		}
+	default:
+		v.add(matchTypeNode, ProblemError, fmt.Sprintf("invalid match-type `%s`, must be one of %s, %s or %s",
+			matchTypeNode.Value, ExactMatch, PrefixMatch, RegexMatch))
+	}
+}
+
+// checkRules checks the rules of an allowed and a blocked list. Rule IDs are
+// only checked for the top-level lists, override rules share the IDs of the
+// rules they replace.
+func (v *configValidator) checkRules(allowedNode, blockedNode *yaml.Node, checkIDs bool) {
+	allowed := v.rules("allowed", allowedNode)
+	blocked := v.rules("blocked", blockedNode)
+
+	for _, rules := range [][]configRule{allowed, blocked} {
+		v.checkDuplicates(rules)
+		v.checkShadowedPrefixes(rules)
+	}
+
+	for _, a := range allowed {
+		for _, b := range blocked {
+			if a.module == b.module {
+				v.add(a.moduleNode, ProblemWarning, fmt.Sprintf(
+					"module `%s` is in both the allowed and blocked lists, the blocked rule at line %d takes precedence",
+					a.module, b.node.Line))
+			}
+		}
+	}
+
+	if checkIDs {
+		v.checkRuleIDs(slices.Concat(allowed, blocked))
+	}
+}
+
+// rules returns the rules of a list and reports malformed rules.
+func (v *configValidator) rules(list string, node *yaml.Node) []configRule {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	rules := make([]configRule, 0, len(node.Content))
+
+	for _, entry := range node.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+
+		rule := configRule{list: list, node: entry, moduleNode: entry, matchTypeNode: entry}
+
+		if n := mappingValue(entry, "module"); n != nil {
+			rule.module, rule.moduleNode = strings.TrimSpace(n.Value), n
+		}
+
+		if n := mappingValue(entry, "match-type"); n != nil {
+			rule.matchType, rule.matchTypeNode = MatchType(n.Value), n
+		}
+
+		if n := mappingValue(entry, "id"); n != nil {
+			rule.id, rule.idNode = n.Value, n
+		}
+
+		v.checkSeverity(mappingValue(entry, "severity"))
+		v.checkRewrite(mappingValue(entry, "rewrite"))
+
+		if rule.module == "" {
+			v.add(entry, ProblemError, fmt.Sprintf("%s rule has no module", list))
+			continue
+		}
+
+		switch rule.matchType.orDefault() {
+		case ExactMatch, PrefixMatch:
+		case RegexMatch:
+			v.checkRegex(rule)
+		default:
+			v.add(rule.matchTypeNode, ProblemError, fmt.Sprintf("invalid match-type `%s`, must be one of %s, %s or %s",
+				rule.matchType, ExactMatch, PrefixMatch, RegexMatch))
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// checkSeverity reports a severity that is not a known severity.
+func (v *configValidator) checkSeverity(node *yaml.Node) {
+	if node != nil && !Severity(node.Value).valid() {
+		v.add(node, ProblemError, fmt.Sprintf("invalid severity `%s`, %s", node.Value, severityValues))
+	}
+}
+
+// checkExpiryWarningDays reports a negative expiry warning window.
+func (v *configValidator) checkExpiryWarningDays(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+
+	if days, err := strconv.Atoi(node.Value); err == nil && days < 0 {
+		v.add(node, ProblemError, fmt.Sprintf("invalid expiry_warning_days `%d`, must not be negative", days))
+	}
+}
+
+// checkRewrite reports rewrites that are not between valid import paths.
+func (v *configValidator) checkRewrite(node *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for _, n := range node.Content {
+		if err := module.CheckImportPath(n.Value); err != nil {
+			v.add(n, ProblemError, fmt.Sprintf("invalid rewrite import path `%s`: %s", n.Value, err))
+		}
+	}
+}
+
+// checkRegex reports regexes that do not compile or can never match.
+func (v *configValidator) checkRegex(rule configRule) {
+	if _, err := regexp.Compile(rule.module); err != nil {
+		v.add(rule.moduleNode, ProblemError, fmt.Sprintf("invalid regex `%s`: %s", rule.module, err))
+		return
+	}
+
+	if !canMatchModulePath(rule.module) {
+		v.add(rule.moduleNode, ProblemWarning, fmt.Sprintf("regex `%s` can never match a module path", rule.module))
+	}
+}
+
+// checkDuplicates reports rules for a module that already has a rule in the
+// same list, the last rule for a module wins.
+func (v *configValidator) checkDuplicates(rules []configRule) {
+	first := make(map[string]configRule, len(rules))
+
+	for _, rule := range rules {
+		if prev, ok := first[rule.module]; ok {
+			v.add(rule.moduleNode, ProblemWarning, fmt.Sprintf(
+				"duplicate %s rule for module `%s`, it replaces the rule at line %d", rule.list, rule.module, prev.node.Line))
+
+			continue
+		}
+
+		first[rule.module] = rule
+	}
+}
+
+// checkShadowedPrefixes reports prefix rules that do not apply to the modules
+// matched by a longer prefix rule of the same list.
+func (v *configValidator) checkShadowedPrefixes(rules []configRule) {
+	for _, short := range rules {
+		if short.matchType != PrefixMatch {
+			continue
+		}
+
+		for _, long := range rules {
+			if long.matchType != PrefixMatch || len(long.module) <= len(short.module) ||
+				!strings.HasPrefix(strings.ToLower(long.module), strings.ToLower(short.module)) {
+				continue
+			}
+
+			v.add(short.moduleNode, ProblemWarning, fmt.Sprintf(
+				"%s prefix rule `%s` is shadowed by the longer prefix rule `%s` at line %d for the modules it matches",
+				short.list, short.module, long.module, long.node.Line))
+		}
+	}
+}
+
+// checkRuleIDs reports rule IDs used by more than one rule or reserved for a
+// built-in rule.
+func (v *configValidator) checkRuleIDs(rules []configRule) {
+	ids := make(map[string]configRule, len(rules))
+
+	for _, rule := range rules {
+		if isBuiltinRuleID(rule.id) {
+			v.add(rule.idNode, ProblemError, fmt.Sprintf("rule id `%s` is reserved for a built-in rule", rule.id))
+
+			continue
+		}
+
+		id := rule.id
+		if id == "" {
+			id = deriveRuleID(rule.list, rule.module)
+		}
+
+		prev, ok := ids[id]
+		if ok && (rule.id != "" || prev.id != "") {
+			node := rule.idNode
+			if node == nil {
+				node = rule.node
+			}
+
+			v.add(node, ProblemError, fmt.Sprintf("rule id `%s` is already used by the rule at line %d", id, prev.node.Line))
+
+			continue
+		}
+
+		ids[id] = rule
+	}
+}
+
+// mappingKey returns the key node of a mapping node, or nil.
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+// mappingValue returns the value node of a key of a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// modulePathChars are the characters a module path can contain.
+const modulePathChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-._~/+"
+
+// matchState is a state of the search for a module path a regex matches.
+type matchState struct {
+	pc uint32
+	// atStart is true when no character was consumed, atEnd is true when an
+	// end of text assertion was passed so no character can follow.
+	atStart bool
+	atEnd   bool
+}
+
+// canMatchModulePath returns true if the regex matches some non-empty string
+// of module path characters. Word boundary assertions are assumed to hold.
+func canMatchModulePath(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return false
+	}
+
+	start := matchState{pc: uint32(prog.Start), atStart: true} //nolint:gosec // Program counters are small.
+	visited := map[matchState]bool{start: true}
+	queue := []matchState{start}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		inst := prog.Inst[s.pc]
+
+		var next []matchState
+
+		switch inst.Op {
+		case syntax.InstMatch:
+			if !s.atStart || !s.atEnd {
+				return true
+			}
+		case syntax.InstFail:
+		case syntax.InstAlt, syntax.InstAltMatch:
+			next = append(next, matchState{inst.Out, s.atStart, s.atEnd}, matchState{inst.Arg, s.atStart, s.atEnd})
+		case syntax.InstCapture, syntax.InstNop:
+			next = append(next, matchState{inst.Out, s.atStart, s.atEnd})
+		case syntax.InstEmptyWidth:
+			empty := syntax.EmptyOp(inst.Arg)
+			if empty&(syntax.EmptyBeginLine|syntax.EmptyBeginText) != 0 && !s.atStart {
+				continue
+			}
+
+			atEnd := s.atEnd || empty&(syntax.EmptyEndLine|syntax.EmptyEndText) != 0
+			next = append(next, matchState{inst.Out, s.atStart, atEnd})
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			if !s.atEnd && strings.ContainsFunc(modulePathChars, func(r rune) bool { return inst.MatchRune(r) }) {
+				next = append(next, matchState{inst.Out, false, false})
+			}
+		}
+
+		for _, n := range next {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestValidateConfiguration(t *testing.T) { //nolint:funlen
+	tests := map[string]struct {
+		config string
+		want   []string
+	}{
+		"valid config": {
+			config: "allowed:\n" +
+				"  - module: golang.org\n" +
+				"    match-type: prefix\n" +
+				"blocked:\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"    recommendations:\n" +
+				"      - github.com/google/uuid\n",
+			want: []string{},
+		},
+		"unknown field and invalid match type": {
+			config: "allowed:\n" +
+				"  - module: golang.org\n" +
+				"    match-type: regexp\n" +
+				"blocked:\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"    recomendations:\n" +
+				"      - github.com/google/uuid\n",
+			want: []string{
+				"CONFIG:3:17: error: invalid match-type `regexp`, must be one of exact, prefix or regex",
+				"CONFIG:6:5: error: field recomendations not found in type gomodguard.BlockedModule",
+			},
+		},
+		"invalid severities": {
+			config: "not_allowed_severity: warn\n" +
+				"blocked:\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"    severity: fatal\n" +
+				"  - module: github.com/mitchellh/go-homedir\n" +
+				"    severity: info\n",
+			want: []string{
+				"CONFIG:1:23: error: invalid severity `warn`, must be one of error, warning or info",
+				"CONFIG:4:15: error: invalid severity `fatal`, must be one of error, warning or info",
+			},
+		},
+		"invalid rewrite": {
+			config: "blocked:\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"    rewrite:\n" +
+				"      github.com/gofrs/uuid: \"github.com/google/uuid \"\n",
+			want: []string{
+				"CONFIG:4:30: error: invalid rewrite import path `github.com/google/uuid `: malformed import path " +
+					"\"github.com/google/uuid \": invalid char ' '",
+			},
+		},
+		"invalid replace rules": {
+			config: "replace:\n" +
+				"  fork:\n" +
+				"    blocked:\n" +
+				"      - old: \"example.com/(\"\n" +
+				"        old-match-type: regex\n" +
+				"        new: github.com/fork/\n" +
+				"        new-match-type: glob\n" +
+				"        severity: fatal\n",
+			want: []string{
+				"CONFIG:4:14: error: invalid regex `example.com/(`: error parsing regexp: missing closing ): `example.com/(`",
+				"CONFIG:7:25: error: invalid match-type `glob`, must be one of exact, prefix or regex",
+				"CONFIG:8:19: error: invalid severity `fatal`, must be one of error, warning or info",
+			},
+		},
+		"invalid tools": {
+			config: "tools:\n" +
+				"  - path: golang.org/x/tools/cmd/\n" +
+				"    match-type: glob\n" +
+				"  - match-type: prefix\n",
+			want: []string{
+				"CONFIG:3:17: error: invalid match-type `glob`, must be one of exact, prefix or regex",
+				"CONFIG:4:5: error: tools rule has no path",
+			},
+		},
+		"deprecated local replace directives": {
+			config: "local_replace_directives: true\n",
+			want: []string{
+				"CONFIG:1:1: warning: local_replace_directives is deprecated, block local replaces with " +
+					"replace.local.blocked instead",
+			},
+		},
+		"syntax error": {
+			config: "allowed:\n  - module: golang.org\n   bad: :\n",
+			want: []string{
+				"CONFIG:2:4: error: yaml: while parsing a block collection at line 1, column 3: line 2, column 4: " +
+					"did not find expected '-' indicator",
+			},
+		},
+		"invalid regex and invalid version constraint": {
+			config: "blocked:\n" +
+				"  - module: \"github.com/(foo\"\n" +
+				"    match-type: regex\n" +
+				"    version: \"abc\"\n",
+			want: []string{
+				"CONFIG:2:13: error: invalid regex `github.com/(foo`: error parsing regexp: missing closing ): `github.com/(foo`",
+				"CONFIG:4:14: error: improper constraint: \"abc\"",
+			},
+		},
+		"warnings": {
+			config: "allowed:\n" +
+				"  - module: github.com/foo\n" +
+				"    match-type: prefix\n" +
+				"  - module: github.com/foo/bar\n" +
+				"    match-type: prefix\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"blocked:\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"  - module: github.com/gofrs/uuid\n" +
+				"  - module: \"^example\\\\.com$/x\"\n" +
+				"    match-type: regex\n" +
+				"  - module: \"[ ]+\"\n" +
+				"    match-type: regex\n",
+			want: []string{
+				"CONFIG:2:13: warning: allowed prefix rule `github.com/foo` is shadowed by the longer prefix rule " +
+					"`github.com/foo/bar` at line 4 for the modules it matches",
+				"CONFIG:6:13: warning: module `github.com/gofrs/uuid` is in both the allowed and blocked lists, the " +
+					"blocked rule at line 8 takes precedence",
+				"CONFIG:6:13: warning: module `github.com/gofrs/uuid` is in both the allowed and blocked lists, the " +
+					"blocked rule at line 9 takes precedence",
+				"CONFIG:9:13: warning: duplicate blocked rule for module `github.com/gofrs/uuid`, it replaces the rule at line 8",
+				"CONFIG:10:13: warning: regex `^example\\.com$/x` can never match a module path",
+				"CONFIG:12:13: warning: regex `[ ]+` can never match a module path",
+			},
+		},
+		"duplicate rule ids and invalid override path": {
+			config: "allowed:\n" +
+				"  - module: github.com/foo\n" +
+				"    id: foo\n" +
+				"blocked:\n" +
+				"  - module: github.com/bar\n" +
+				"    id: foo\n" +
+				"overrides:\n" +
+				"  \"cmd/[\":\n" +
+				"    allowed:\n" +
+				"      - module: github.com/baz\n",
+			want: []string{
+				"CONFIG:6:9: error: rule id `foo` is already used by the rule at line 2",
+				"CONFIG:8:3: error: invalid override path `cmd/[`: syntax error in pattern",
+			},
+		},
+		"reserved rule id": {
+			config: "blocked:\n" +
+				"  - module: github.com/foo\n" +
+				"    id: unused-suppression\n",
+			want: []string{
+				"CONFIG:3:9: error: rule id `unused-suppression` is reserved for a built-in rule",
+			},
+		},
+		"negative expiry warning days": {
+			config: "expiry_warning_days: -7\n",
+			want: []string{
+				"CONFIG:1:22: error: invalid expiry_warning_days `-7`, must not be negative",
+			},
+		},
+		"missing extended config file": {
+			config: "extends:\n  - missing.yaml\n",
+			want: []string{
+				"CONFIG:1:1: error: config file CONFIG: could not find extended config file: missing.yaml",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), ".gomodguard.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0o600))
+
+			got := []string{}
+			for _, problem := range gomodguard.ValidateConfiguration(configPath, "") {
+				got = append(got, problem.String())
+			}
+
+			want := make([]string, 0, len(tt.want))
+			for _, w := range tt.want {
+				want = append(want, strings.ReplaceAll(w, "CONFIG", configPath))
+			}
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
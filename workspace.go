@@ -0,0 +1,204 @@
+package gomodguard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	errReadingGoWorkFile = "unable to read workspace file %s: %w"
+	errParsingGoWorkFile = "unable to parse workspace file %s: %w"
+)
+
+// Workspace lints the modules of a go.work workspace, checking the imports of
+// each file against the go.mod file of the module the file belongs to.
+type Workspace struct {
+	Config   *Configuration
+	Workfile *modfile.WorkFile
+	// Processors holds a processor for each module used by the workspace, in
+	// the order of the use directives.
+	Processors []*Processor
+
+	byModFile map[string]*Processor
+}
+
+// NewWorkspace returns a workspace for the go.work file at goWorkFilePath
+// with a processor for each module it uses.
+func NewWorkspace(config *Configuration, goWorkFilePath string) (*Workspace, error) {
+	goWorkFileBytes, err := os.ReadFile(filepath.Clean(goWorkFilePath))
+	if err != nil {
+		return nil, fmt.Errorf(errReadingGoWorkFile, goWorkFilePath, err)
+	}
+
+	workFile, err := modfile.ParseWork(goWorkFilePath, goWorkFileBytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errParsingGoWorkFile, goWorkFilePath, err)
+	}
+
+	if absPath, err := filepath.Abs(goWorkFilePath); err == nil {
+		goWorkFilePath = absPath
+	}
+
+	w := &Workspace{
+		Config:     config,
+		Workfile:   workFile,
+		Processors: make([]*Processor, 0, len(workFile.Use)),
+		byModFile:  make(map[string]*Processor, len(workFile.Use)),
+	}
+
+	for _, use := range workFile.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(goWorkFilePath), dir)
+		}
+
+		p, err := NewProcessorFromModFile(config, filepath.Join(dir, goModFilename))
+		if err != nil {
+			return nil, err
+		}
+
+		w.Processors = append(w.Processors, p)
+		w.byModFile[p.modFilePath] = p
+	}
+
+	return w, nil
+}
+
+// Processor returns the processor of the workspace module whose go.mod file
+// is at goModFilePath, or nil if the workspace does not use that module.
+func (w *Workspace) Processor(goModFilePath string) *Processor {
+	if absPath, err := filepath.Abs(goModFilePath); err == nil {
+		goModFilePath = absPath
+	}
+
+	return w.byModFile[goModFilePath]
+}
+
+// ProcessFiles checks the imports of each file against the module it belongs
+// to. Files that do not belong to a module used by the workspace are skipped,
+// the go command does not build them either.
+func (w *Workspace) ProcessFiles(filenames []string) (issues []Issue) {
+	filesByProcessor := w.filesByProcessor(filenames)
+
+	for _, p := range w.Processors {
+		issues = append(issues, p.ProcessFiles(filesByProcessor[p])...)
+	}
+
+	return issues
+}
+
+// FixFiles returns the fixes of FixFiles for the files of every module of the
+// workspace. Files that do not belong to a module used by the workspace are
+// skipped.
+func (w *Workspace) FixFiles(filenames []string) (fixes []FileFix) {
+	filesByProcessor := w.filesByProcessor(filenames)
+
+	for _, p := range w.Processors {
+		fixes = append(fixes, p.FixFiles(filesByProcessor[p])...)
+	}
+
+	return fixes
+}
+
+// FixModFiles returns the fixes of FixModFiles for the go.mod file of every
+// module of the workspace.
+func (w *Workspace) FixModFiles() ([]ModFileFix, error) {
+	var fixes []ModFileFix
+
+	for _, p := range w.Processors {
+		fix, err := p.FixModFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		fixes = append(fixes, fix...)
+	}
+
+	return fixes, nil
+}
+
+// filesByProcessor groups the files by the processor of the module they
+// belong to.
+func (w *Workspace) filesByProcessor(filenames []string) map[*Processor][]string {
+	modFileOfDir := make(map[string]string)
+	filesByProcessor := make(map[*Processor][]string, len(w.Processors))
+
+	for _, filename := range filenames {
+		dir := filepath.Dir(filename)
+
+		goModFilePath, ok := modFileOfDir[dir]
+		if !ok {
+			goModFilePath, _ = FindGoModFile(dir)
+			modFileOfDir[dir] = goModFilePath
+		}
+
+		if p := w.byModFile[goModFilePath]; p != nil {
+			filesByProcessor[p] = append(filesByProcessor[p], filename)
+		}
+	}
+
+	return filesByProcessor
+}
+
+// ProcessRequires returns the issues of ProcessRequires for every module of
+// the workspace.
+func (w *Workspace) ProcessRequires() (issues []Issue) {
+	for _, p := range w.Processors {
+		issues = append(issues, p.ProcessRequires()...)
+	}
+
+	return issues
+}
+
+// ProcessModFile returns the issues of ProcessModFile for every module of the
+// workspace.
+func (w *Workspace) ProcessModFile() (issues []Issue) {
+	for _, p := range w.Processors {
+		issues = append(issues, p.ProcessModFile()...)
+	}
+
+	return issues
+}
+
+// UnusedRules returns the rules that UnusedRules returns for every module of
+// the workspace, so rules used by any module are not reported.
+func (w *Workspace) UnusedRules() []UnusedRule {
+	if len(w.Processors) == 0 {
+		return nil
+	}
+
+	unused := w.Processors[0].UnusedRules()
+
+	for _, p := range w.Processors[1:] {
+		unusedByModule := p.UnusedRules()
+
+		unused = slices.DeleteFunc(unused, func(r UnusedRule) bool {
+			return !slices.Contains(unusedByModule, r)
+		})
+	}
+
+	return unused
+}
+
+// FindGoWorkFile returns the path of the go.work file the go command uses in
+// the working directory, or an empty string when it is not in a workspace or
+// workspace mode is disabled with GOWORK=off.
+func FindGoWorkFile() string {
+	out, err := exec.Command("go", "env", "GOWORK").Output() //nolint:noctx // Ack at some point might use os/exec.CommandContext.
+	if err != nil {
+		return ""
+	}
+
+	goWorkFilePath := strings.TrimSpace(string(out))
+	if goWorkFilePath == "off" {
+		return ""
+	}
+
+	return goWorkFilePath
+}
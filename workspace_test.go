@@ -0,0 +1,62 @@
+package gomodguard_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ryancurrah/gomodguard/v2"
+)
+
+func TestWorkspaceProcessFiles(t *testing.T) {
+	t.Chdir("examples/workspace")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	workspace, err := gomodguard.NewWorkspace(&gomodguard.Configuration{
+		Blocked: gomodguard.Blocked{
+			{
+				Module:  "github.com/mitchellh/go-homedir",
+				Version: mustConstraint(t, "< 1.1.0"),
+				Reason:  "testing if each workspace module is checked against its own go.mod.",
+			},
+		},
+	}, "go.work")
+	require.NoError(t, err)
+	require.Len(t, workspace.Processors, 2)
+
+	assert.NotNil(t, workspace.Processor(filepath.Join("foo", "go.mod")))
+	assert.Nil(t, workspace.Processor(filepath.Join("..", "alloptions", "go.mod")))
+
+	files := append(gomodguard.Find(wd, false, []string{"./..."}), filepath.Join("..", "alloptions", "blocked_example.go"))
+
+	issues := workspace.ProcessFiles(files)
+
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+
+	assert.Equal(t, []string{
+		"foo/foo.go:3:1 import of package `github.com/mitchellh/go-homedir` is blocked because the module is in the " +
+			"blocked modules list. version `v1.0.0` is blocked because it does not meet the version constraint `<1.1.0`. " +
+			"testing if each workspace module is checked against its own go.mod. (blocked/github.com/mitchellh/go-homedir)",
+	}, got)
+
+	issues = workspace.ProcessRequires()
+	require.Len(t, issues, 1)
+	assert.Equal(t, filepath.Join("foo", "go.mod"), issues[0].FileName)
+	assert.Equal(t, 5, issues[0].LineNumber)
+}
+
+func TestWorkspaceNewWorkspaceMissingModule(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.25.0\n\nuse ./missing\n"), 0o600))
+
+	_, err := gomodguard.NewWorkspace(&gomodguard.Configuration{}, filepath.Join(dir, "go.work"))
+	require.Error(t, err)
+}